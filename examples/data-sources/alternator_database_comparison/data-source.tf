@@ -0,0 +1,12 @@
+data "alternator_database_comparison" "staging_to_production" {
+  source_database = "example"
+  source_host     = "staging.example.com:3306"
+  database        = "example"
+}
+
+check "production_matches_staging" {
+  assert {
+    condition     = data.alternator_database_comparison.staging_to_production.in_sync
+    error_message = "Production differs from staging: ${data.alternator_database_comparison.staging_to_production.change_summary}"
+  }
+}
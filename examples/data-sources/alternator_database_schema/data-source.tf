@@ -1,3 +1,14 @@
 data "alternator_database_schema" "example" {
   database = "example"
 }
+
+# Fetch only some tables as JSON
+data "alternator_database_schema" "users" {
+  database = "example"
+  tables   = ["users", "user_profiles"]
+  format   = "json"
+}
+
+output "users_columns" {
+  value = jsondecode(data.alternator_database_schema.users.table_schemas["users"]).columns
+}
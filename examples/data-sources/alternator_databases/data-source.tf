@@ -0,0 +1,9 @@
+data "alternator_databases" "tenants" {
+  name_regex = "^tenant_"
+}
+
+resource "alternator_database_schema" "tenants" {
+  for_each = toset(data.alternator_databases.tenants.names)
+  database = each.value
+  schema   = templatefile("${path.module}/tenant.sql", { database = each.value })
+}
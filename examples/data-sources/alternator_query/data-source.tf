@@ -0,0 +1,10 @@
+data "alternator_query" "tenants" {
+  database   = "example"
+  query      = "SELECT id, name FROM tenants WHERE region = ? ORDER BY id"
+  parameters = ["ap-northeast-1"]
+}
+
+resource "alternator_database" "tenants" {
+  for_each = { for r in data.alternator_query.tenants.rows : r.id => r }
+  name     = "tenant_${each.value.name}"
+}
@@ -0,0 +1,11 @@
+data "alternator_schema_diff" "example" {
+  database = "example"
+  schema   = file("${path.module}/schema.sql")
+}
+
+check "schema_in_sync" {
+  assert {
+    condition     = data.alternator_schema_diff.example.in_sync
+    error_message = "Database example differs from schema.sql: ${data.alternator_schema_diff.example.change_summary}"
+  }
+}
@@ -0,0 +1,7 @@
+data "alternator_server_info" "example" {
+  variables = ["innodb_default_row_format", "max_connections"]
+}
+
+output "instant_ddl" {
+  value = data.alternator_server_info.example.instant_ddl
+}
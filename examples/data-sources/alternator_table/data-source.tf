@@ -0,0 +1,8 @@
+data "alternator_table" "users" {
+  database = "example"
+  name     = "users"
+}
+
+output "user_columns" {
+  value = [for c in data.alternator_table.users.columns : c.name]
+}
@@ -0,0 +1,7 @@
+# Convert tables of the database from utf8 to utf8mb4, 10 tables per apply
+resource "alternator_charset_migration" "example" {
+  database         = "example"
+  character_set    = "utf8mb4"
+  collation        = "utf8mb4_0900_ai_ci"
+  tables_per_apply = 10
+}
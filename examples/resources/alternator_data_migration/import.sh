@@ -0,0 +1,3 @@
+# Import ID is "database:version", or "database:version:tracking_table" if tracking_table is not the default one
+$ terraform import alternator_data_migration.example example:20240101_backfill_display_name
+$ terraform import alternator_data_migration.example example:20240101_backfill_display_name:data_migrations
@@ -0,0 +1,18 @@
+resource "alternator_data_migration" "backfill_display_name" {
+  database = "example"
+  version  = "20240101_backfill_display_name"
+  sql      = "UPDATE users SET display_name = name WHERE display_name IS NULL"
+
+  depends_on = [alternator_database_schema.example]
+}
+
+# Update a large table by 10000 rows at a time
+resource "alternator_data_migration" "normalize_emails" {
+  database = "example"
+  version  = "20240102_normalize_emails"
+  sql      = "UPDATE users SET email = LOWER(email) WHERE id >= {{chunk_start}} AND id < {{chunk_end}}"
+  chunk {
+    table = "users"
+    size  = 10000
+  }
+}
@@ -0,0 +1 @@
+$ terraform import alternator_database.example example
@@ -0,0 +1,17 @@
+resource "alternator_database" "example" {
+  name          = "example"
+  character_set = "utf8mb4"
+  collation     = "utf8mb4_0900_ai_ci"
+}
+
+resource "alternator_table" "users" {
+  database   = alternator_database.example.name
+  name       = "users"
+  definition = <<EOF
+CREATE TABLE users
+(
+    id   int PRIMARY KEY,
+    name varchar(100)
+);
+EOF
+}
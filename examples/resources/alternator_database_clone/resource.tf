@@ -0,0 +1,24 @@
+resource "alternator_database_clone" "preview" {
+  source_database = "staging"
+  source_host     = "staging-db.example.com:3306"
+  database        = "preview_pr_123"
+  copy_data       = true
+  row_limit       = 10000
+
+  masking {
+    table  = "users"
+    column = "email"
+    method = "hash"
+  }
+  masking {
+    table  = "users"
+    column = "phone"
+    method = "null"
+  }
+  masking {
+    table  = "users"
+    column = "password_hash"
+    method = "fixed"
+    value  = "$2a$10$invalidinvalidinvalidinvalidinvalidinvalidinvalidinva"
+  }
+}
@@ -1 +1,4 @@
-$ terraform import alternator_database_schema.example example
\ No newline at end of file
+$ terraform import alternator_database_schema.example example
+
+# The database name can be prefixed by the provider host when multiple providers are used
+$ terraform import alternator_database_schema.example localhost:3306/example
@@ -18,3 +18,17 @@ CREATE TABLE blog_posts
 );
 EOF
 }
+
+# Ephemeral test database populated from fixtures on creation
+resource "alternator_database_schema" "test" {
+  database = "example_test"
+  schema   = file("${path.module}/schema_test.sql")
+
+  initial_data {
+    sql_file = "${path.module}/fixtures/users.sql"
+  }
+  initial_data {
+    csv_file = "${path.module}/fixtures/blog_posts.csv"
+    table    = "blog_posts"
+  }
+}
@@ -0,0 +1,6 @@
+# Privileges, in the form of "user@host:database.table" or "user@host:database.table(column1,column2)"
+$ terraform import alternator_grant.reader reader@%:example.*
+$ terraform import alternator_grant.app_users app@%:example.users(email,name)
+
+# Roles, in the form of "user@host"
+$ terraform import alternator_grant.app_roles app@%
@@ -0,0 +1,23 @@
+# Database level privileges granted to a role
+resource "alternator_grant" "reader" {
+  user       = alternator_role.reader.name
+  database   = "example"
+  privileges = ["SELECT"]
+}
+
+# Column level privileges
+resource "alternator_grant" "app_users" {
+  user       = alternator_user.app.user
+  host       = alternator_user.app.host
+  database   = "example"
+  table      = "users"
+  columns    = ["name", "email"]
+  privileges = ["UPDATE"]
+}
+
+# Roles
+resource "alternator_grant" "app_roles" {
+  user  = alternator_user.app.user
+  host  = alternator_user.app.host
+  roles = [alternator_role.reader.name]
+}
@@ -0,0 +1,29 @@
+resource "alternator_database_schema" "example" {
+  database          = "example"
+  ignore_partitions = ["events"]
+  schema            = <<EOF
+CREATE DATABASE example;
+USE example;
+CREATE TABLE events
+(
+    id         bigint,
+    created_at date,
+    PRIMARY KEY (id, created_at)
+)
+PARTITION BY RANGE (TO_DAYS(created_at)) (
+    PARTITION p20240101 VALUES LESS THAN (TO_DAYS('2024-01-02'))
+);
+EOF
+}
+
+# Keep partitions from 30 days ago to 7 days ahead
+resource "alternator_partition_rotation" "events" {
+  database  = "example"
+  table     = "events"
+  column    = "created_at"
+  interval  = "day"
+  premake   = 7
+  retention = 30
+
+  depends_on = [alternator_database_schema.example]
+}
@@ -0,0 +1 @@
+$ terraform import alternator_role.example reader
@@ -0,0 +1,3 @@
+resource "alternator_role" "example" {
+  name = "reader"
+}
@@ -0,0 +1,6 @@
+resource "alternator_server_variables" "example" {
+  variables = {
+    sql_require_primary_key          = "ON"
+    innodb_online_alter_log_max_size = "268435456"
+  }
+}
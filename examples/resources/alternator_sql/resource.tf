@@ -0,0 +1,17 @@
+resource "alternator_sql" "example" {
+  database    = "example"
+  create_sql  = "INSERT IGNORE INTO feature_flags (name, enabled) VALUES ('new_checkout', false)"
+  destroy_sql = "DELETE FROM feature_flags WHERE name = 'new_checkout'"
+  read_query  = "SELECT name, enabled FROM feature_flags WHERE name = 'new_checkout'"
+
+  depends_on = [alternator_database_schema.example]
+}
+
+# Execute the statement again on every schema change
+resource "alternator_sql" "analyze" {
+  database   = "example"
+  create_sql = "ANALYZE TABLE users"
+  triggers = {
+    schema = alternator_database_schema.example.remote_schema
+  }
+}
@@ -0,0 +1 @@
+$ terraform import alternator_table.example example.users
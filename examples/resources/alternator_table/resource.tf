@@ -0,0 +1,11 @@
+resource "alternator_table" "example" {
+  database   = "example"
+  name       = "users"
+  definition = <<EOF
+CREATE TABLE users
+(
+    id   int PRIMARY KEY,
+    name varchar(100)
+);
+EOF
+}
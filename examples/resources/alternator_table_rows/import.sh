@@ -0,0 +1 @@
+$ terraform import alternator_table_rows.example example.plans
@@ -0,0 +1,12 @@
+resource "alternator_table_rows" "example" {
+  database = "example"
+  table    = "plans"
+  keys     = ["code"]
+  rows = [
+    { code = "free", name = "Free", price = "0" },
+    { code = "pro", name = "Professional", price = "10" },
+    { code = "team", name = "Team", price = "20" },
+  ]
+
+  depends_on = [alternator_database_schema.example]
+}
@@ -0,0 +1 @@
+$ terraform import alternator_user.example app@%
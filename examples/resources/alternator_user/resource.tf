@@ -0,0 +1,7 @@
+resource "alternator_user" "example" {
+  user                 = "app"
+  host                 = "%"
+  password_wo          = var.app_password
+  password_wo_version  = 1
+  max_user_connections = 100
+}
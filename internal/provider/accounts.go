@@ -0,0 +1,171 @@
+package provider
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/go-sql-driver/mysql"
+	"sort"
+	"strings"
+)
+
+// MySQL error number of "There is no such grant defined for user"
+const errNoSuchGrant = 1141
+
+type showGrant struct {
+	// Privileges with their column lists. The same privilege can appear at both table and column level.
+	Privileges []*showPrivilege
+	// Target of the privileges such as "`db`.`table`", or empty string for role grants
+	Target string
+	// Granted roles formatted by formatRole
+	Roles       []string
+	GrantOption bool
+}
+
+type showPrivilege struct {
+	Name string
+	// Columns are empty for table level privileges
+	Columns []string
+}
+
+// quoteString quotes the string as a SQL string literal.
+func quoteString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `'`, `''`)
+	return fmt.Sprintf("'%s'", s)
+}
+
+func quoteAccount(user string, host string) string {
+	return fmt.Sprintf("%s@%s", quoteString(user), quoteString(host))
+}
+
+// formatRole formats the role account as its name, with the host only if it is not "%".
+func formatRole(user string, host string) string {
+	if host == "%" {
+		return user
+	}
+	return fmt.Sprintf("%s@%s", user, host)
+}
+
+// quoteRole quotes the role formatted by formatRole.
+func quoteRole(role string) string {
+	user, host, ok := strings.Cut(role, "@")
+	if !ok {
+		host = "%"
+	}
+	return quoteAccount(user, host)
+}
+
+// grantTarget returns the privilege level such as "`db`.*".
+func grantTarget(database string, table string) string {
+	ret := "*"
+	if database != "*" {
+		ret = fmt.Sprintf("`%s`", database)
+	}
+	if table == "*" {
+		return ret + ".*"
+	}
+	return fmt.Sprintf("%s.`%s`", ret, table)
+}
+
+// fetchGrants returns grants of the account by SHOW GRANTS, or nil if the account does not exist.
+func fetchGrants(db *sql.DB, user string, host string) ([]*showGrant, error) {
+	rows, err := db.Query(fmt.Sprintf("SHOW GRANTS FOR %s", quoteAccount(user, host)))
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == errNoSuchGrant {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to show grants : %w", err)
+	}
+	defer rows.Close()
+
+	ret := []*showGrant{}
+	for rows.Next() {
+		var line string
+		err := rows.Scan(&line)
+		if err != nil {
+			return nil, fmt.Errorf("failed to show grants : %w", err)
+		}
+		g, err := parseShowGrant(line)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, g)
+	}
+	return ret, rows.Err()
+}
+
+// parseShowGrant parses a line of SHOW GRANTS output, for example:
+//
+//	GRANT SELECT (`id`, `name`), INSERT ON `db`.`users` TO `app`@`%` WITH GRANT OPTION
+//	GRANT `reader`@`%` TO `app`@`%`
+func parseShowGrant(line string) (*showGrant, error) {
+	body, ok := strings.CutPrefix(line, "GRANT ")
+	if !ok {
+		return nil, fmt.Errorf("unexpected grant: %s", line)
+	}
+	ret := &showGrant{Privileges: []*showPrivilege{}, Roles: []string{}}
+	if s, ok := strings.CutSuffix(body, " WITH GRANT OPTION"); ok {
+		body = s
+		ret.GrantOption = true
+	} else if s, ok := strings.CutSuffix(body, " WITH ADMIN OPTION"); ok {
+		body = s
+		ret.GrantOption = true
+	}
+	i := strings.LastIndex(body, " TO ")
+	if i < 0 {
+		return nil, fmt.Errorf("unexpected grant: %s", line)
+	}
+	body = body[:i]
+
+	i = strings.LastIndex(body, " ON ")
+	if i < 0 {
+		for _, r := range splitTopLevel(body) {
+			user, host, ok := strings.Cut(r, "@")
+			if !ok {
+				return nil, fmt.Errorf("unexpected role: %s", r)
+			}
+			ret.Roles = append(ret.Roles, formatRole(strings.Trim(user, "`'"), strings.Trim(host, "`'")))
+		}
+		sort.Strings(ret.Roles)
+		return ret, nil
+	}
+
+	ret.Target = body[i+len(" ON "):]
+	for _, p := range splitTopLevel(body[:i]) {
+		name, columns, ok := strings.Cut(p, "(")
+		name = strings.TrimSpace(name)
+		if !ok {
+			ret.Privileges = append(ret.Privileges, &showPrivilege{Name: name, Columns: []string{}})
+			continue
+		}
+		cs := []string{}
+		for _, c := range strings.Split(strings.TrimSuffix(columns, ")"), ",") {
+			cs = append(cs, strings.Trim(strings.TrimSpace(c), "`"))
+		}
+		ret.Privileges = append(ret.Privileges, &showPrivilege{Name: name, Columns: cs})
+	}
+	return ret, nil
+}
+
+// splitTopLevel splits the comma-separated list, ignoring commas in parentheses.
+func splitTopLevel(s string) []string {
+	ret := []string{}
+	depth := 0
+	start := 0
+	for i, c := range s {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				ret = append(ret, strings.TrimSpace(s[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return append(ret, strings.TrimSpace(s[start:]))
+}
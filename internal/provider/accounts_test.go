@@ -0,0 +1,114 @@
+package provider
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestParseShowGrant(t *testing.T) {
+	g, err := parseShowGrant("GRANT SELECT (`id`, `name`), INSERT, UPDATE (`name`) ON `example`.`users` TO `app`@`%` WITH GRANT OPTION")
+	require.NoError(t, err)
+	require.Equal(t, "`example`.`users`", g.Target)
+	require.Equal(t, []*showPrivilege{
+		{Name: "SELECT", Columns: []string{"id", "name"}},
+		{Name: "INSERT", Columns: []string{}},
+		{Name: "UPDATE", Columns: []string{"name"}},
+	}, g.Privileges)
+	require.True(t, g.GrantOption)
+
+	// The same privilege at table level and column level
+	g, err = parseShowGrant("GRANT SELECT, SELECT (`name`) ON `example`.`users` TO `app`@`%`")
+	require.NoError(t, err)
+	require.Equal(t, []*showPrivilege{
+		{Name: "SELECT", Columns: []string{}},
+		{Name: "SELECT", Columns: []string{"name"}},
+	}, g.Privileges)
+
+	g, err = parseShowGrant("GRANT `reader`@`%`,`writer`@`localhost` TO `app`@`%`")
+	require.NoError(t, err)
+	require.Equal(t, "", g.Target)
+	require.Equal(t, []string{"reader", "writer@localhost"}, g.Roles)
+	require.False(t, g.GrantOption)
+
+	_, err = parseShowGrant("REVOKE SELECT ON *.* FROM `app`@`%`")
+	require.Error(t, err)
+}
+
+func TestGrantedPrivileges(t *testing.T) {
+	grants := []*showGrant{}
+	for _, line := range []string{
+		"GRANT USAGE ON *.* TO `app`@`%`",
+		"GRANT SELECT, INSERT ON `example`.* TO `app`@`%`",
+		"GRANT SELECT (`id`, `name`), UPDATE (`name`) ON `example`.`users` TO `app`@`%`",
+	} {
+		g, err := parseShowGrant(line)
+		require.NoError(t, err)
+		grants = append(grants, g)
+	}
+
+	privileges, grantOption := grantedPrivileges(grants, grantTarget("example", "*"), nil)
+	require.Equal(t, []string{"INSERT", "SELECT"}, privileges)
+	require.False(t, grantOption)
+
+	privileges, _ = grantedPrivileges(grants, grantTarget("example", "users"), []string{"name"})
+	require.Equal(t, []string{"SELECT", "UPDATE"}, privileges)
+
+	privileges, _ = grantedPrivileges(grants, grantTarget("*", "*"), nil)
+	require.Empty(t, privileges)
+
+	g, err := parseShowGrant("GRANT SELECT, INSERT (`name`), SELECT (`name`) ON `example`.`posts` TO `app`@`%`")
+	require.NoError(t, err)
+	privileges, _ = grantedPrivileges([]*showGrant{g}, grantTarget("example", "posts"), nil)
+	require.Equal(t, []string{"SELECT"}, privileges)
+	privileges, _ = grantedPrivileges([]*showGrant{g}, grantTarget("example", "posts"), []string{"name"})
+	require.Equal(t, []string{"INSERT", "SELECT"}, privileges)
+}
+
+func TestGrantedRoles(t *testing.T) {
+	grants := []*showGrant{}
+	for _, line := range []string{
+		"GRANT USAGE ON *.* TO `app`@`%`",
+		"GRANT `reader`@`%` TO `app`@`%`",
+		"GRANT `writer`@`localhost` TO `app`@`%` WITH ADMIN OPTION",
+	} {
+		g, err := parseShowGrant(line)
+		require.NoError(t, err)
+		grants = append(grants, g)
+	}
+
+	roles, grantOption := grantedRoles(grants, []string{"reader"})
+	require.Equal(t, []string{"reader"}, roles)
+	require.False(t, grantOption)
+
+	roles, grantOption = grantedRoles(grants, []string{"writer@localhost"})
+	require.Equal(t, []string{"writer@localhost"}, roles)
+	require.True(t, grantOption)
+
+	roles, _ = grantedRoles(grants, nil)
+	require.Equal(t, []string{"reader", "writer@localhost"}, roles)
+}
+
+func TestGrantId(t *testing.T) {
+	id := grantId("app", "%", "example", "users", []string{"id", "name"})
+	require.Equal(t, "app@%:example.users(id,name)", id)
+
+	user, host, database, table, columns, err := parseGrantId(id)
+	require.NoError(t, err)
+	require.Equal(t, "app", user)
+	require.Equal(t, "%", host)
+	require.Equal(t, "example", database)
+	require.Equal(t, "users", table)
+	require.Equal(t, []string{"id", "name"}, columns)
+
+	_, _, database, _, _, err = parseGrantId("app@localhost")
+	require.NoError(t, err)
+	require.Equal(t, "", database)
+
+	_, _, _, _, _, err = parseGrantId("app")
+	require.Error(t, err)
+}
+
+func TestPrivilegeList(t *testing.T) {
+	require.Equal(t, "SELECT, INSERT", privilegeList([]string{"SELECT", "INSERT"}, nil))
+	require.Equal(t, "SELECT (`id`, `name`), GRANT OPTION", privilegeList([]string{"SELECT", "GRANT OPTION"}, []string{"id", "name"}))
+}
@@ -0,0 +1,154 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/kota65535/alternator/lib"
+	"strings"
+)
+
+func dataSourceAlternatorDatabaseComparison() *schema.Resource {
+	plannedChanges := plannedChangesSchema()
+	plannedChanges.Description = "Structured statements to make the target database match the source database."
+
+	return &schema.Resource{
+		Description: "Compare the schemas of two SQL databases, possibly on different servers. " +
+			"Schema objects other than tables, such as views and triggers, are compared only by their existence, because their definitions contain server specific values such as definers.",
+		ReadContext: dataSourceAlternatorDatabaseComparisonRead,
+
+		Schema: map[string]*schema.Schema{
+			"source_database": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Database name to compare from.",
+			},
+			"source_host": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Host of the source database, such as `localhost:3306`. Defaults to the provider host.",
+			},
+			"source_user": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "User of the source database. Defaults to the provider user.",
+			},
+			"source_password": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				Description: "Password of the source database. Defaults to the provider password.",
+			},
+			"database": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Target database name on the provider host.",
+			},
+			"ignore_partitions": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Tables whose partition definitions are ignored. The partitioning method is still compared.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"in_sync": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the target database matches the source database.",
+			},
+			"statements": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Statements to make the target database match the source database.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"planned_changes": plannedChanges,
+			"change_summary": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Summary of the differences, such as `+1 table, ~2 columns, -1 index`.",
+			},
+		},
+	}
+}
+
+func dataSourceAlternatorDatabaseComparisonRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	sourceDatabase := d.Get("source_database").(string)
+	database := d.Get("database").(string)
+	pp := meta.(*ProviderArguments)
+	sp := sourceProviderArguments(d, pp)
+
+	source, err := newAlternator(sourceDatabase, sp)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer source.Close()
+
+	sourceSchemas, err := source.FetchSchemas()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if len(sourceSchemas) == 0 {
+		return diag.FromErr(fmt.Errorf("%w: %s", errDatabaseNotFound, sourceDatabase))
+	}
+	sourceObjects, err := fetchSchemaObjects(source.Db, sourceDatabase)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("failed to fetch source schema objects : %w", err))
+	}
+	schemaStr := comparisonSchemaString(sourceSchemas[0], sourceObjects, sourceDatabase, database)
+	tflog.Debug(ctx, fmt.Sprintf("@read source schema: %s", schemaStr))
+
+	client, err := newAlternator(database, pp)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer client.Close()
+
+	// cf. dataSourceAlternatorSchemaDiffRead
+	diff, err := getSchemaDiff(client, database, schemaStr, schemaStr, nil, expandStringList(d.Get("ignore_partitions")))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	statements := diff.Statements()
+	tflog.Debug(ctx, fmt.Sprintf("@read statements: %s", statements))
+
+	d.SetId(fmt.Sprintf("%s:%s", sourceDatabase, database))
+	err = d.Set("in_sync", len(statements) == 0)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	err = d.Set("statements", statements)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	err = d.Set("planned_changes", flattenPlannedChanges(diff.Changes))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	err = d.Set("change_summary", changeSummary(diff.Changes))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+// comparisonSchemaString returns the schema definition of the source database as if it is the database of the given name.
+func comparisonSchemaString(s *lib.Schema, objects []*schemaObject, sourceDatabase string, database string) string {
+	ret := ""
+	for _, statement := range cloneSchemaStatements(s, database) {
+		ret += fmt.Sprintf("%s;\n", statement)
+	}
+	renamed := []*schemaObject{}
+	for _, o := range objects {
+		r := *o
+		r.Definition = strings.ReplaceAll(o.Definition, fmt.Sprintf("`%s`.", sourceDatabase), fmt.Sprintf("`%s`.", database))
+		renamed = append(renamed, &r)
+	}
+	return ret + formatSchemaObjects(renamed)
+}
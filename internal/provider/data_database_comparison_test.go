@@ -0,0 +1,64 @@
+package provider
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/stretchr/testify/require"
+	"regexp"
+	"testing"
+)
+
+func TestAccDataSourceAlternatorDatabaseComparison(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			// Out of sync
+			{
+				PreConfig: func() {
+					db, err := sql.Open("mysql", "root@tcp(localhost:23306)/?multiStatements=true")
+					require.NoError(t, err)
+					_, err = db.Exec("DROP DATABASE IF EXISTS staging; DROP DATABASE IF EXISTS production; " +
+						"CREATE DATABASE staging; CREATE DATABASE production; " +
+						"CREATE TABLE staging.users (id int PRIMARY KEY, name varchar(100), email varchar(255)); " +
+						"CREATE VIEW staging.user_names AS SELECT name FROM staging.users; " +
+						"CREATE TABLE production.users (id int PRIMARY KEY, name varchar(100))")
+					require.NoError(t, err)
+				},
+				Config: testAccDataSourceAlternatorDatabaseComparisonConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.alternator_database_comparison.main", "id", "staging:production"),
+					resource.TestCheckResourceAttr("data.alternator_database_comparison.main", "in_sync", "false"),
+					resource.TestCheckResourceAttr("data.alternator_database_comparison.main", "statements.#", "2"),
+					resource.TestMatchResourceAttr("data.alternator_database_comparison.main", "statements.0", regexp.MustCompile("ALTER TABLE `production`.`users` ADD COLUMN `email`")),
+					resource.TestMatchResourceAttr("data.alternator_database_comparison.main", "statements.1", regexp.MustCompile("CREATE .*VIEW `user_names`")),
+				),
+			},
+			// In sync
+			{
+				PreConfig: func() {
+					db, err := sql.Open("mysql", "root@tcp(localhost:23306)/?multiStatements=true")
+					require.NoError(t, err)
+					_, err = db.Exec("ALTER TABLE production.users ADD COLUMN email varchar(255); " +
+						"CREATE VIEW production.user_names AS SELECT name FROM production.users")
+					require.NoError(t, err)
+				},
+				Config: testAccDataSourceAlternatorDatabaseComparisonConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.alternator_database_comparison.main", "in_sync", "true"),
+					resource.TestCheckResourceAttr("data.alternator_database_comparison.main", "change_summary", "no changes"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceAlternatorDatabaseComparisonConfig() string {
+	return fmt.Sprintf(`
+	%s
+	data "alternator_database_comparison" "main" {
+        source_database = "staging"
+        database        = "production"
+	}
+	`, provider)
+}
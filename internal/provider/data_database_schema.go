@@ -2,10 +2,14 @@ package provider
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/kota65535/alternator/lib"
+	"github.com/kota65535/alternator/parser"
 )
 
 func dataSourceAlternatorDatabaseSchema() *schema.Resource {
@@ -20,17 +24,51 @@ func dataSourceAlternatorDatabaseSchema() *schema.Resource {
 				Required:    true,
 				Description: "Target database name.",
 			},
+			"tables": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Tables to fetch. Omit it to fetch all the tables. Schema objects other than triggers of the tables are not fetched if specified.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"exclude_tables": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Tables not to fetch, including their triggers.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"format": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "sql",
+				Description:  "Format of `remote_schema` and `table_schemas`. Either `sql` or `json`. The JSON has the same structure as `tables` attribute of `alternator_database_schema` resource.",
+				ValidateFunc: validation.StringInSlice([]string{"sql", "json"}, false),
+			},
 			"remote_schema": {
 				Type:        schema.TypeString,
 				Computed:    true,
 				Description: "Actual remote database schema definition.",
 			},
+			"table_schemas": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Description: "Table names and their definitions.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
 		},
 	}
 }
 
 func dataSourceAlternatorDatabaseSchemaRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	database := d.Get("database").(string)
+	tables := expandStringList(d.Get("tables"))
+	excludeTables := expandStringList(d.Get("exclude_tables"))
+	format := d.Get("format").(string)
 	pp := meta.(*ProviderArguments)
 	client, err := newAlternator(database, pp)
 	if err != nil {
@@ -42,14 +80,126 @@ func dataSourceAlternatorDatabaseSchemaRead(ctx context.Context, d *schema.Resou
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	remoteSchemaStr := ""
+	remoteObjects, err := fetchSchemaObjects(client.Db, database)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	filteredSchema := []*lib.Schema{}
 	for _, s := range remoteSchema {
-		remoteSchemaStr += fmt.Sprintf("%s\n", s)
+		filtered, err := filterSchemaTables(s, tables, excludeTables)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		filteredSchema = append(filteredSchema, filtered)
+	}
+	remoteObjects = filterSchemaObjects(remoteObjects, tables, excludeTables)
+
+	remoteSchemaStr := ""
+	tableSchemas := map[string]string{}
+	if format == "json" {
+		remoteSchemaStr, tableSchemas, err = schemaJson(database, filteredSchema, remoteObjects)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	} else {
+		for _, s := range filteredSchema {
+			remoteSchemaStr += fmt.Sprintf("%s\n", s)
+			for _, t := range s.Tables {
+				tableSchemas[t.TableName] = tableDefinitionString(t)
+			}
+		}
+		remoteSchemaStr += formatSchemaObjects(remoteObjects)
 	}
 	tflog.Debug(ctx, fmt.Sprintf("@read remote_schema: %s", remoteSchemaStr))
 
 	d.SetId(database)
 	d.Set("remote_schema", remoteSchemaStr)
+	err = d.Set("table_schemas", tableSchemas)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
 	return nil
 }
+
+// filterSchemaTables returns a copy of the schema only with the given tables, except the excluded ones.
+// All the tables are included if tables is empty.
+func filterSchemaTables(s *lib.Schema, tables []string, excludeTables []string) (*lib.Schema, error) {
+	for _, name := range tables {
+		if findTable(s, name) == nil {
+			return nil, fmt.Errorf("table %s.%s not found", s.Database.DbName, name)
+		}
+	}
+	ret := *s
+	ret.Tables = []*parser.CreateTableStatement{}
+	for _, t := range s.Tables {
+		if includesTable(t.TableName, tables, excludeTables) {
+			ret.Tables = append(ret.Tables, t)
+		}
+	}
+	return &ret, nil
+}
+
+// filterSchemaObjects returns the triggers of the included tables, and the other objects only if tables is empty.
+func filterSchemaObjects(objects []*schemaObject, tables []string, excludeTables []string) []*schemaObject {
+	ret := []*schemaObject{}
+	for _, o := range objects {
+		if o.Type == "trigger" {
+			if includesTable(o.Table, tables, excludeTables) {
+				ret = append(ret, o)
+			}
+		} else if len(tables) == 0 {
+			ret = append(ret, o)
+		}
+	}
+	return ret
+}
+
+func includesTable(name string, tables []string, excludeTables []string) bool {
+	for _, e := range excludeTables {
+		if e == name {
+			return false
+		}
+	}
+	if len(tables) == 0 {
+		return true
+	}
+	for _, t := range tables {
+		if t == name {
+			return true
+		}
+	}
+	return false
+}
+
+// schemaJson returns the schema as a JSON document, and the tables as JSON documents keyed by their names.
+func schemaJson(database string, schemas []*lib.Schema, objects []*schemaObject) (string, map[string]string, error) {
+	tables := flattenSchemaTables(schemas)
+	tableSchemas := map[string]string{}
+	for _, t := range tables {
+		table := t.(map[string]interface{})
+		b, err := json.Marshal(table)
+		if err != nil {
+			return "", nil, err
+		}
+		tableSchemas[table["name"].(string)] = string(b)
+	}
+	flattenedObjects := []interface{}{}
+	for _, o := range objects {
+		flattenedObjects = append(flattenedObjects, map[string]interface{}{
+			"type":       o.Type,
+			"name":       o.Name,
+			"table":      o.Table,
+			"definition": o.Definition,
+		})
+	}
+	b, err := json.Marshal(map[string]interface{}{
+		"database": database,
+		"tables":   tables,
+		"objects":  flattenedObjects,
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	return string(b), tableSchemas, nil
+}
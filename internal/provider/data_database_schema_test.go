@@ -4,9 +4,14 @@ import (
 	"database/sql"
 	_ "embed"
 	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/kota65535/alternator/lib"
+	"github.com/kota65535/alternator/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAccDataSourceAlternatorDatabaseSchema(t *testing.T) {
@@ -31,8 +36,21 @@ func TestAccDataSourceAlternatorDatabaseSchema(t *testing.T) {
 				Config: testAccDataSourceAlternatorDatabaseSchemaInitialConfig(),
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr("data.alternator_database_schema.main", "remote_schema", initialSchemaRemote),
+					resource.TestMatchResourceAttr("data.alternator_database_schema.main", "table_schemas.greeting", regexp.MustCompile("^CREATE TABLE `greeting`")),
 				),
 			},
+			{
+				Config: testAccDataSourceAlternatorDatabaseSchemaFilterConfig("json"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.alternator_database_schema.main", "table_schemas.%", "1"),
+					resource.TestMatchResourceAttr("data.alternator_database_schema.main", "table_schemas.greeting", regexp.MustCompile(`"name":"greeting"`)),
+					resource.TestMatchResourceAttr("data.alternator_database_schema.main", "remote_schema", regexp.MustCompile(`^\{"database":"example"`)),
+				),
+			},
+			{
+				Config:      testAccDataSourceAlternatorDatabaseSchemaMissingTableConfig(),
+				ExpectError: regexp.MustCompile("table example.missing not found"),
+			},
 		},
 	})
 }
@@ -45,3 +63,77 @@ func testAccDataSourceAlternatorDatabaseSchemaInitialConfig() string {
 	}
 	`, provider)
 }
+
+func testAccDataSourceAlternatorDatabaseSchemaFilterConfig(format string) string {
+	return fmt.Sprintf(`
+	%s
+	data "alternator_database_schema" "main" {
+        database = "example"
+        tables   = ["greeting"]
+        format   = "%s"
+	}
+	`, provider, format)
+}
+
+func testAccDataSourceAlternatorDatabaseSchemaMissingTableConfig() string {
+	return fmt.Sprintf(`
+	%s
+	data "alternator_database_schema" "main" {
+        database = "example"
+        tables   = ["missing"]
+	}
+	`, provider)
+}
+
+func TestFilterSchemaTables(t *testing.T) {
+	s := &lib.Schema{
+		Database: &parser.CreateDatabaseStatement{DbName: "example"},
+		Tables: []*parser.CreateTableStatement{
+			{TableName: "users"},
+			{TableName: "posts"},
+			{TableName: "audit_logs"},
+		},
+	}
+	names := func(s *lib.Schema) []string {
+		ret := []string{}
+		for _, t := range s.Tables {
+			ret = append(ret, t.TableName)
+		}
+		return ret
+	}
+
+	filtered, err := filterSchemaTables(s, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"users", "posts", "audit_logs"}, names(filtered))
+
+	filtered, err = filterSchemaTables(s, []string{"posts", "users"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"users", "posts"}, names(filtered))
+
+	filtered, err = filterSchemaTables(s, nil, []string{"audit_logs"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"users", "posts"}, names(filtered))
+	assert.Len(t, s.Tables, 3)
+
+	_, err = filterSchemaTables(s, []string{"missing"}, nil)
+	assert.EqualError(t, err, "table example.missing not found")
+}
+
+func TestFilterSchemaObjects(t *testing.T) {
+	objects := []*schemaObject{
+		{Type: "view", Name: "active_users"},
+		{Type: "trigger", Name: "users_audit", Table: "users"},
+		{Type: "trigger", Name: "posts_audit", Table: "posts"},
+	}
+	keys := func(objects []*schemaObject) []string {
+		ret := []string{}
+		for _, o := range objects {
+			ret = append(ret, o.Name)
+		}
+		return ret
+	}
+
+	assert.Equal(t, []string{"active_users", "users_audit", "posts_audit"}, keys(filterSchemaObjects(objects, nil, nil)))
+	assert.Equal(t, []string{"users_audit"}, keys(filterSchemaObjects(objects, []string{"users"}, nil)))
+	assert.Equal(t, []string{"active_users", "posts_audit"}, keys(filterSchemaObjects(objects, nil, []string{"users"})))
+}
@@ -0,0 +1,158 @@
+package provider
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"regexp"
+)
+
+// systemDatabases are the databases created by the server itself.
+var systemDatabases = map[string]bool{
+	"information_schema": true,
+	"mysql":              true,
+	"performance_schema": true,
+	"sys":                true,
+}
+
+type databaseSummary struct {
+	Name         string
+	CharacterSet string
+	Collation    string
+}
+
+func dataSourceAlternatorDatabases() *schema.Resource {
+	return &schema.Resource{
+		Description: "List SQL databases on the server.",
+		ReadContext: dataSourceAlternatorDatabasesRead,
+
+		Schema: map[string]*schema.Schema{
+			"name_regex": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Regular expression to filter database names.",
+				ValidateFunc: validation.StringIsValidRegExp,
+			},
+			"include_system": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether to include system databases such as `mysql` and `information_schema`.",
+			},
+			"names": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Names of the databases in alphabetical order.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"databases": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Databases in alphabetical order.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Database name.",
+						},
+						"character_set": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Default character set of the database.",
+						},
+						"collation": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Default collation of the database.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceAlternatorDatabasesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pp := meta.(*ProviderArguments)
+	client, err := newAlternator("", pp)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer client.Close()
+
+	databases, err := fetchDatabases(client.Db)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	nameRegex := d.Get("name_regex").(string)
+	databases, err = filterDatabases(databases, nameRegex, d.Get("include_system").(bool))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	names := []string{}
+	flattened := []interface{}{}
+	for _, s := range databases {
+		names = append(names, s.Name)
+		flattened = append(flattened, map[string]interface{}{
+			"name":          s.Name,
+			"character_set": s.CharacterSet,
+			"collation":     s.Collation,
+		})
+	}
+	tflog.Debug(ctx, fmt.Sprintf("@read names: %s", names))
+
+	d.SetId(fmt.Sprintf("databases:%s", nameRegex))
+	err = d.Set("names", names)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	err = d.Set("databases", flattened)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func fetchDatabases(db *sql.DB) ([]*databaseSummary, error) {
+	rows, err := db.Query("SELECT SCHEMA_NAME, DEFAULT_CHARACTER_SET_NAME, DEFAULT_COLLATION_NAME FROM information_schema.SCHEMATA ORDER BY SCHEMA_NAME")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list databases : %w", err)
+	}
+	defer rows.Close()
+	ret := []*databaseSummary{}
+	for rows.Next() {
+		s := &databaseSummary{}
+		err := rows.Scan(&s.Name, &s.CharacterSet, &s.Collation)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list databases : %w", err)
+		}
+		ret = append(ret, s)
+	}
+	return ret, rows.Err()
+}
+
+func filterDatabases(databases []*databaseSummary, nameRegex string, includeSystem bool) ([]*databaseSummary, error) {
+	re, err := regexp.Compile(nameRegex)
+	if err != nil {
+		return nil, err
+	}
+	ret := []*databaseSummary{}
+	for _, s := range databases {
+		if !includeSystem && systemDatabases[s.Name] {
+			continue
+		}
+		if re.MatchString(s.Name) {
+			ret = append(ret, s)
+		}
+	}
+	return ret, nil
+}
@@ -0,0 +1,74 @@
+package provider
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestFilterDatabases(t *testing.T) {
+	databases := []*databaseSummary{
+		{Name: "information_schema"},
+		{Name: "mysql"},
+		{Name: "tenant_a"},
+		{Name: "tenant_b"},
+		{Name: "work"},
+	}
+
+	names := func(databases []*databaseSummary) []string {
+		ret := []string{}
+		for _, s := range databases {
+			ret = append(ret, s.Name)
+		}
+		return ret
+	}
+
+	filtered, err := filterDatabases(databases, "", false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tenant_a", "tenant_b", "work"}, names(filtered))
+
+	filtered, err = filterDatabases(databases, "^tenant_", false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tenant_a", "tenant_b"}, names(filtered))
+
+	filtered, err = filterDatabases(databases, "", true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"information_schema", "mysql", "tenant_a", "tenant_b", "work"}, names(filtered))
+}
+
+func TestAccDataSourceAlternatorDatabases(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() {
+					db, err := sql.Open("mysql", "root@tcp(localhost:23306)/?multiStatements=true")
+					require.NoError(t, err)
+					_, err = db.Exec("DROP DATABASE IF EXISTS tenant_1; DROP DATABASE IF EXISTS tenant_2; " +
+						"CREATE DATABASE tenant_1 CHARACTER SET utf8mb4 COLLATE utf8mb4_bin; CREATE DATABASE tenant_2")
+					require.NoError(t, err)
+				},
+				Config: testAccDataSourceAlternatorDatabasesConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.alternator_databases.main", "names.#", "2"),
+					resource.TestCheckResourceAttr("data.alternator_databases.main", "names.0", "tenant_1"),
+					resource.TestCheckResourceAttr("data.alternator_databases.main", "names.1", "tenant_2"),
+					resource.TestCheckResourceAttr("data.alternator_databases.main", "databases.0.character_set", "utf8mb4"),
+					resource.TestCheckResourceAttr("data.alternator_databases.main", "databases.0.collation", "utf8mb4_bin"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceAlternatorDatabasesConfig() string {
+	return fmt.Sprintf(`
+	%s
+	data "alternator_databases" "main" {
+        name_regex = "^tenant_[0-9]+$"
+	}
+	`, provider)
+}
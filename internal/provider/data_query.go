@@ -0,0 +1,207 @@
+package provider
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"regexp"
+	"time"
+)
+
+var readOnlyQueryPattern = regexp.MustCompile(`(?i)^(SELECT|WITH)\b`)
+
+type queryColumn struct {
+	Name     string
+	Type     string
+	Nullable bool
+}
+
+func dataSourceAlternatorQuery() *schema.Resource {
+	return &schema.Resource{
+		Description: "Execute a single `SELECT` query in a read-only transaction and fetch the result.",
+		ReadContext: dataSourceAlternatorQueryRead,
+
+		Schema: map[string]*schema.Schema{
+			"database": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Database to execute the query in.",
+			},
+			"query": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "`SELECT` query to execute. Use `?` placeholders for `parameters`.",
+			},
+			"parameters": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Values bound to the placeholders of `query`.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"max_rows": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      1000,
+				Description:  "Maximum number of rows. The query fails if it returns more rows.",
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"timeout": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      30,
+				Description:  "Timeout of the query in seconds.",
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"columns": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Columns of the result.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Column name.",
+						},
+						"type": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Database type name of the column, such as `VARCHAR` or `INT`.",
+						},
+						"nullable": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the column may be NULL.",
+						},
+					},
+				},
+			},
+			"rows": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Rows of the result, as maps from column names to values. NULL columns are omitted.",
+				Elem: &schema.Schema{
+					Type: schema.TypeMap,
+					Elem: &schema.Schema{
+						Type: schema.TypeString,
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceAlternatorQueryRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	database := d.Get("database").(string)
+	query := d.Get("query").(string)
+	pp := meta.(*ProviderArguments)
+
+	err := validateReadOnlyQuery(query)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	args := []interface{}{}
+	for _, p := range expandStringList(d.Get("parameters")) {
+		args = append(args, p)
+	}
+
+	client, err := newAlternator("", pp)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(d.Get("timeout").(int))*time.Second)
+	defer cancel()
+	tflog.Debug(ctx, fmt.Sprintf("@read executing query: %s", query))
+	columns, rows, err := queryReadOnly(ctx, client.Db, database, query, args, d.Get("max_rows").(int))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	flattenedColumns := []interface{}{}
+	for _, c := range columns {
+		flattenedColumns = append(flattenedColumns, map[string]interface{}{
+			"name":     c.Name,
+			"type":     c.Type,
+			"nullable": c.Nullable,
+		})
+	}
+
+	d.SetId(fmt.Sprintf("%s:%s", database, query))
+	err = d.Set("columns", flattenedColumns)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	err = d.Set("rows", flattenRows(rows))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+// validateReadOnlyQuery returns an error unless the query is a single SELECT statement.
+// DDL statements must be rejected beforehand, because they implicitly commit even a read-only transaction.
+func validateReadOnlyQuery(query string) error {
+	statements := splitStatements(query)
+	if len(statements) != 1 {
+		return fmt.Errorf("query must be a single statement, but found %d", len(statements))
+	}
+	if !readOnlyQueryPattern.MatchString(stripLeadingComments(statements[0])) {
+		return fmt.Errorf("query must be a SELECT statement: %s", statements[0])
+	}
+	return nil
+}
+
+// queryReadOnly executes the query in a read-only transaction, and returns the columns and at most limit rows.
+func queryReadOnly(ctx context.Context, db *sql.DB, database string, query string, args []interface{}, limit int) ([]*queryColumn, []map[string]string, error) {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer conn.Close()
+	if database != "" {
+		_, err = conn.ExecContext(ctx, fmt.Sprintf("USE `%s`", database))
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	tx, err := conn.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, nil, err
+	}
+	// Nothing to commit
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to execute query : %w", err)
+	}
+	defer rows.Close()
+	types, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, nil, err
+	}
+	columns := []*queryColumn{}
+	for _, t := range types {
+		nullable, _ := t.Nullable()
+		columns = append(columns, &queryColumn{
+			Name:     t.Name(),
+			Type:     t.DatabaseTypeName(),
+			Nullable: nullable,
+		})
+	}
+	ret, err := scanRows(rows, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+	return columns, ret, nil
+}
@@ -0,0 +1,70 @@
+package provider
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"regexp"
+	"testing"
+)
+
+func TestValidateReadOnlyQuery(t *testing.T) {
+	assert.NoError(t, validateReadOnlyQuery("SELECT 1"))
+	assert.NoError(t, validateReadOnlyQuery("-- tenants\nselect id FROM tenants;"))
+	assert.NoError(t, validateReadOnlyQuery("WITH t AS (SELECT 1 AS n) SELECT n FROM t"))
+	assert.EqualError(t, validateReadOnlyQuery("SELECT 1; SELECT 2"), "query must be a single statement, but found 2")
+	assert.EqualError(t, validateReadOnlyQuery("DROP TABLE tenants"), "query must be a SELECT statement: DROP TABLE tenants")
+}
+
+func TestAccDataSourceAlternatorQuery(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() {
+					db, err := sql.Open("mysql", "root@tcp(localhost:23306)/?multiStatements=true")
+					require.NoError(t, err)
+					_, err = db.Exec("DROP DATABASE IF EXISTS example13; CREATE DATABASE example13; " +
+						"CREATE TABLE example13.tenants (id int PRIMARY KEY, name varchar(100) NOT NULL, shard int); " +
+						"INSERT INTO example13.tenants VALUES (1, 'acme', 1), (2, 'globex', NULL), (3, 'initech', 2)")
+					require.NoError(t, err)
+				},
+				Config: testAccDataSourceAlternatorQueryConfig("SELECT id, name, shard FROM tenants WHERE id >= ? ORDER BY id", 10),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.alternator_query.main", "rows.#", "2"),
+					resource.TestCheckResourceAttr("data.alternator_query.main", "rows.0.name", "globex"),
+					resource.TestCheckNoResourceAttr("data.alternator_query.main", "rows.0.shard"),
+					resource.TestCheckResourceAttr("data.alternator_query.main", "rows.1.shard", "2"),
+					resource.TestCheckResourceAttr("data.alternator_query.main", "columns.#", "3"),
+					resource.TestCheckResourceAttr("data.alternator_query.main", "columns.0.type", "INT"),
+					resource.TestCheckResourceAttr("data.alternator_query.main", "columns.1.type", "VARCHAR"),
+					resource.TestCheckResourceAttr("data.alternator_query.main", "columns.1.nullable", "false"),
+				),
+			},
+			// Too many rows
+			{
+				Config:      testAccDataSourceAlternatorQueryConfig("SELECT id FROM tenants WHERE id >= ?", 1),
+				ExpectError: regexp.MustCompile("query returned more than 1 rows"),
+			},
+			// Not a SELECT statement
+			{
+				Config:      testAccDataSourceAlternatorQueryConfig("DELETE FROM tenants WHERE id >= ?", 10),
+				ExpectError: regexp.MustCompile("query must be a SELECT statement"),
+			},
+		},
+	})
+}
+
+func testAccDataSourceAlternatorQueryConfig(query string, maxRows int) string {
+	return fmt.Sprintf(`
+	%s
+	data "alternator_query" "main" {
+        database   = "example13"
+        query      = "%s"
+        parameters = ["2"]
+        max_rows   = %d
+	}
+	`, provider, query, maxRows)
+}
@@ -0,0 +1,99 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceAlternatorSchemaDiff() *schema.Resource {
+	plannedChanges := plannedChangesSchema()
+	plannedChanges.Description = "Structured statements to make the database match the schema."
+
+	return &schema.Resource{
+		Description: "Compare SQL database with the expected schema without changing it. " +
+			"Schema objects other than tables, such as views and triggers, are compared only by their existence, because the server rewrites their definitions.",
+		ReadContext: dataSourceAlternatorSchemaDiffRead,
+
+		Schema: map[string]*schema.Schema{
+			"database": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Target database name.",
+			},
+			"schema": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Expected database schema definition.",
+			},
+			"ignore_partitions": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Tables whose partition definitions are ignored. The partitioning method is still compared.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"in_sync": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the database matches the schema.",
+			},
+			"statements": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Statements to make the database match the schema.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"planned_changes": plannedChanges,
+			"change_summary": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Summary of the differences, such as `+1 table, ~2 columns, -1 index`.",
+			},
+		},
+	}
+}
+
+func dataSourceAlternatorSchemaDiffRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	database := d.Get("database").(string)
+	schemaStr := d.Get("schema").(string)
+	pp := meta.(*ProviderArguments)
+	client, err := newAlternator(database, pp)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer client.Close()
+
+	// The schema itself is regarded as the previous one, so that definitions of schema objects are not compared
+	diff, err := getSchemaDiff(client, database, schemaStr, schemaStr, nil, expandStringList(d.Get("ignore_partitions")))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	statements := diff.Statements()
+	tflog.Debug(ctx, fmt.Sprintf("@read statements: %s", statements))
+
+	d.SetId(database)
+	err = d.Set("in_sync", len(statements) == 0)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	err = d.Set("statements", statements)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	err = d.Set("planned_changes", flattenPlannedChanges(diff.Changes))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	err = d.Set("change_summary", changeSummary(diff.Changes))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
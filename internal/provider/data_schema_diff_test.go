@@ -0,0 +1,55 @@
+package provider
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestAccDataSourceAlternatorSchemaDiff(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			// In sync
+			{
+				PreConfig: func() {
+					db, err := sql.Open("mysql", "root@tcp(localhost:23306)/?multiStatements=true")
+					require.NoError(t, err)
+					_, err = db.Exec("DROP DATABASE IF EXISTS example")
+					require.NoError(t, err)
+					_, err = db.Exec(initialSchema)
+					require.NoError(t, err)
+				},
+				Config: testAccDataSourceAlternatorSchemaDiffConfig(initialSchema),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.alternator_schema_diff.main", "in_sync", "true"),
+					resource.TestCheckResourceAttr("data.alternator_schema_diff.main", "statements.#", "0"),
+					resource.TestCheckResourceAttr("data.alternator_schema_diff.main", "change_summary", "no changes"),
+				),
+			},
+			// Out of sync
+			{
+				Config: testAccDataSourceAlternatorSchemaDiffConfig(updatedSchema),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.alternator_schema_diff.main", "in_sync", "false"),
+					resource.TestCheckResourceAttrSet("data.alternator_schema_diff.main", "statements.0"),
+					resource.TestCheckResourceAttrSet("data.alternator_schema_diff.main", "planned_changes.0.sql"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceAlternatorSchemaDiffConfig(schema string) string {
+	return fmt.Sprintf(`
+	%s
+	data "alternator_schema_diff" "main" {
+        database = "example"
+        schema = <<EOT
+		%s
+		EOT
+	}
+	`, provider, schema)
+}
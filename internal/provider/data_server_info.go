@@ -0,0 +1,191 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var serverVersionPattern = regexp.MustCompile(`^(\d+)\.(\d+)\.(\d+)`)
+
+type serverVersion struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+func (r serverVersion) atLeast(major int, minor int, patch int) bool {
+	if r.Major != major {
+		return r.Major > major
+	}
+	if r.Minor != minor {
+		return r.Minor > minor
+	}
+	return r.Patch >= patch
+}
+
+func dataSourceAlternatorServerInfo() *schema.Resource {
+	return &schema.Resource{
+		Description: "Fetch the version and variables of SQL database server.",
+		ReadContext: dataSourceAlternatorServerInfoRead,
+
+		Schema: map[string]*schema.Schema{
+			"variables": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Names of global variables to fetch. They are case-insensitive.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"version": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Full version string of the server, such as `8.0.32`.",
+			},
+			"version_comment": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Version comment of the server, such as `MySQL Community Server - GPL`.",
+			},
+			"major_version": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Major version number.",
+			},
+			"minor_version": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Minor version number.",
+			},
+			"patch_version": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Patch version number.",
+			},
+			"flavor": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Flavor of the server. One of `mysql`, `mariadb` or `percona`.",
+			},
+			"lower_case_table_names": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Value of `lower_case_table_names` variable.",
+			},
+			"default_storage_engine": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Default storage engine of the server.",
+			},
+			"instant_ddl": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether columns can be added by `ALGORITHM=INSTANT`.",
+			},
+			"variable_values": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Description: "Values of `variables`.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+func dataSourceAlternatorServerInfoRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	pp := meta.(*ProviderArguments)
+	client, err := newAlternator("", pp)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer client.Close()
+
+	var version, versionComment, defaultStorageEngine string
+	var lowerCaseTableNames int
+	err = client.Db.QueryRow("SELECT VERSION(), @@version_comment, @@lower_case_table_names, @@default_storage_engine").
+		Scan(&version, &versionComment, &lowerCaseTableNames, &defaultStorageEngine)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("failed to query server version : %w", err))
+	}
+	parsed, err := parseServerVersion(version)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	flavor := serverFlavor(version, versionComment)
+	tflog.Debug(ctx, fmt.Sprintf("@read version: %s, flavor: %s", version, flavor))
+
+	names := expandStringList(d.Get("variables"))
+	variables, err := fetchGlobalVariables(client.Db, names)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	// Keyed by the names as specified, because variable names are case-insensitive
+	variableValues := map[string]string{}
+	for _, name := range names {
+		value, ok := variables[strings.ToLower(name)]
+		if !ok {
+			return diag.Errorf("unknown variable %s", name)
+		}
+		variableValues[name] = value
+	}
+
+	d.SetId(pp.Host)
+	values := map[string]interface{}{
+		"version":                version,
+		"version_comment":        versionComment,
+		"major_version":          parsed.Major,
+		"minor_version":          parsed.Minor,
+		"patch_version":          parsed.Patch,
+		"flavor":                 flavor,
+		"lower_case_table_names": lowerCaseTableNames,
+		"default_storage_engine": defaultStorageEngine,
+		"instant_ddl":            instantDdlAvailable(flavor, parsed),
+		"variable_values":        variableValues,
+	}
+	for k, v := range values {
+		err = d.Set(k, v)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return nil
+}
+
+func parseServerVersion(version string) (serverVersion, error) {
+	m := serverVersionPattern.FindStringSubmatch(version)
+	if m == nil {
+		return serverVersion{}, fmt.Errorf("failed to parse server version %s", version)
+	}
+	major, _ := strconv.Atoi(m[1])
+	minor, _ := strconv.Atoi(m[2])
+	patch, _ := strconv.Atoi(m[3])
+	return serverVersion{Major: major, Minor: minor, Patch: patch}, nil
+}
+
+func serverFlavor(version string, versionComment string) string {
+	if strings.Contains(version, "MariaDB") {
+		return "mariadb"
+	}
+	if strings.Contains(versionComment, "Percona") {
+		return "percona"
+	}
+	return "mysql"
+}
+
+// instantDdlAvailable returns whether the server supports ALGORITHM=INSTANT to add columns,
+// which is available since MySQL 8.0.12 and MariaDB 10.3.2.
+func instantDdlAvailable(flavor string, version serverVersion) bool {
+	if flavor == "mariadb" {
+		return version.atLeast(10, 3, 2)
+	}
+	return version.atLeast(8, 0, 12)
+}
@@ -0,0 +1,67 @@
+package provider
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestParseServerVersion(t *testing.T) {
+	v, err := parseServerVersion("8.0.32")
+	require.NoError(t, err)
+	assert.Equal(t, serverVersion{Major: 8, Minor: 0, Patch: 32}, v)
+
+	v, err = parseServerVersion("10.6.12-MariaDB-1:10.6.12+maria~ubu2004")
+	require.NoError(t, err)
+	assert.Equal(t, serverVersion{Major: 10, Minor: 6, Patch: 12}, v)
+
+	_, err = parseServerVersion("unknown")
+	assert.Error(t, err)
+}
+
+func TestServerFlavor(t *testing.T) {
+	assert.Equal(t, "mysql", serverFlavor("8.0.32", "MySQL Community Server - GPL"))
+	assert.Equal(t, "mariadb", serverFlavor("10.6.12-MariaDB-1:10.6.12+maria~ubu2004", "mariadb.org binary distribution"))
+	assert.Equal(t, "percona", serverFlavor("8.0.32-24", "Percona Server (GPL), Release 24"))
+}
+
+func TestInstantDdlAvailable(t *testing.T) {
+	assert.False(t, instantDdlAvailable("mysql", serverVersion{Major: 5, Minor: 7, Patch: 40}))
+	assert.False(t, instantDdlAvailable("mysql", serverVersion{Major: 8, Minor: 0, Patch: 11}))
+	assert.True(t, instantDdlAvailable("mysql", serverVersion{Major: 8, Minor: 0, Patch: 12}))
+	assert.True(t, instantDdlAvailable("percona", serverVersion{Major: 8, Minor: 1, Patch: 0}))
+	assert.False(t, instantDdlAvailable("mariadb", serverVersion{Major: 10, Minor: 2, Patch: 44}))
+	assert.True(t, instantDdlAvailable("mariadb", serverVersion{Major: 10, Minor: 6, Patch: 12}))
+}
+
+func TestAccDataSourceAlternatorServerInfo(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceAlternatorServerInfoConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.alternator_server_info.main", "flavor", "mysql"),
+					resource.TestCheckResourceAttr("data.alternator_server_info.main", "major_version", "8"),
+					resource.TestCheckResourceAttr("data.alternator_server_info.main", "default_storage_engine", "InnoDB"),
+					resource.TestCheckResourceAttr("data.alternator_server_info.main", "instant_ddl", "true"),
+					resource.TestCheckResourceAttrSet("data.alternator_server_info.main", "lower_case_table_names"),
+					resource.TestCheckResourceAttr("data.alternator_server_info.main", "variable_values.%", "2"),
+					resource.TestCheckResourceAttrSet("data.alternator_server_info.main", "variable_values.max_connections"),
+					resource.TestCheckResourceAttr("data.alternator_server_info.main", "variable_values.INNODB_DEFAULT_ROW_FORMAT", "dynamic"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceAlternatorServerInfoConfig() string {
+	return fmt.Sprintf(`
+	%s
+	data "alternator_server_info" "main" {
+        variables = ["max_connections", "INNODB_DEFAULT_ROW_FORMAT"]
+	}
+	`, provider)
+}
@@ -0,0 +1,100 @@
+package provider
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceAlternatorTable() *schema.Resource {
+	s := tableMetadataSchema()
+	s["database"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Required:    true,
+		Description: "Database name.",
+	}
+	s["name"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Required:    true,
+		Description: "Table name.",
+	}
+	s["definition"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "Table definition (CREATE TABLE statement).",
+	}
+	s["row_format"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "Row format of the table, such as `Dynamic`.",
+	}
+	s["comment"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "Comment of the table.",
+	}
+
+	return &schema.Resource{
+		Description: "Fetch a single table definition and its structured metadata.",
+		ReadContext: dataSourceAlternatorTableRead,
+		Schema:      s,
+	}
+}
+
+func dataSourceAlternatorTableRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	database := d.Get("database").(string)
+	name := d.Get("name").(string)
+	pp := meta.(*ProviderArguments)
+	client, err := newAlternator(database, pp)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer client.Close()
+
+	remoteSchemas, err := client.FetchSchemas()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if len(remoteSchemas) == 0 {
+		return diag.FromErr(fmt.Errorf("%w: %s", errDatabaseNotFound, database))
+	}
+	table := findTable(remoteSchemas[0], name)
+	if table == nil {
+		return diag.Errorf("table %s.%s not found", database, name)
+	}
+	var rowFormat sql.NullString
+	var comment string
+	err = client.Db.QueryRow("SELECT ROW_FORMAT, TABLE_COMMENT FROM information_schema.TABLES WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?", database, name).
+		Scan(&rowFormat, &comment)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return diag.FromErr(fmt.Errorf("failed to query table options : %w", err))
+	}
+	definition := tableDefinitionString(table)
+	tflog.Debug(ctx, fmt.Sprintf("@read definition: %s", definition))
+
+	d.SetId(fmt.Sprintf("%s.%s", database, name))
+	for k, v := range flattenTable(table) {
+		err = d.Set(k, v)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	err = d.Set("definition", definition)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	err = d.Set("row_format", rowFormat.String)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	err = d.Set("comment", comment)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
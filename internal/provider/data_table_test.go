@@ -0,0 +1,63 @@
+package provider
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/stretchr/testify/require"
+	"regexp"
+	"testing"
+)
+
+func TestAccDataSourceAlternatorTable(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() {
+					db, err := sql.Open("mysql", "root@tcp(localhost:23306)/?multiStatements=true")
+					require.NoError(t, err)
+					_, err = db.Exec("DROP DATABASE IF EXISTS example12; CREATE DATABASE example12; " +
+						"CREATE TABLE example12.users (id int PRIMARY KEY, name varchar(100) NOT NULL, UNIQUE KEY name (name)) COMMENT 'registered users'; " +
+						"CREATE TABLE example12.posts (id int PRIMARY KEY, user_id int, CONSTRAINT posts_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE)")
+					require.NoError(t, err)
+				},
+				Config: testAccDataSourceAlternatorTableConfig("posts"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.alternator_table.main", "id", "example12.posts"),
+					resource.TestCheckResourceAttr("data.alternator_table.main", "engine", "InnoDB"),
+					resource.TestCheckResourceAttr("data.alternator_table.main", "row_format", "Dynamic"),
+					resource.TestCheckResourceAttr("data.alternator_table.main", "columns.#", "2"),
+					resource.TestCheckResourceAttr("data.alternator_table.main", "columns.1.name", "user_id"),
+					resource.TestCheckResourceAttr("data.alternator_table.main", "foreign_keys.0.name", "posts_user"),
+					resource.TestCheckResourceAttr("data.alternator_table.main", "foreign_keys.0.referenced_table", "users"),
+					resource.TestCheckResourceAttr("data.alternator_table.main", "foreign_keys.0.on_delete", "CASCADE"),
+					resource.TestMatchResourceAttr("data.alternator_table.main", "definition", regexp.MustCompile("^CREATE TABLE `posts`")),
+				),
+			},
+			{
+				Config: testAccDataSourceAlternatorTableConfig("users"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.alternator_table.main", "comment", "registered users"),
+					resource.TestCheckResourceAttr("data.alternator_table.main", "columns.1.nullable", "false"),
+					resource.TestCheckResourceAttr("data.alternator_table.main", "indexes.#", "2"),
+					resource.TestCheckResourceAttr("data.alternator_table.main", "indexes.1.type", "unique"),
+				),
+			},
+			{
+				Config:      testAccDataSourceAlternatorTableConfig("missing"),
+				ExpectError: regexp.MustCompile("table example12.missing not found"),
+			},
+		},
+	})
+}
+
+func testAccDataSourceAlternatorTableConfig(name string) string {
+	return fmt.Sprintf(`
+	%s
+	data "alternator_table" "main" {
+        database = "example12"
+        name     = "%s"
+	}
+	`, provider, name)
+}
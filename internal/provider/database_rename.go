@@ -0,0 +1,142 @@
+package provider
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/kota65535/alternator/cmd"
+	"strings"
+)
+
+// databaseRenameChanges returns changes to rename the database by moving all its tables into a new database.
+// Schema objects are recreated in the new database, because views cannot be moved and tables with triggers cannot be moved.
+// It returns nil if the old database does not exist, and an error if the new database already exists.
+// It also returns whether the old database is read only, which should be restored by readOnlyChange after the schema changes are applied.
+func databaseRenameChanges(db *sql.DB, from string, to string) ([]*plannedChange, bool, error) {
+	options, err := fetchDatabaseOptions(db, from)
+	if err != nil {
+		return nil, false, err
+	}
+	if options == nil {
+		return nil, false, nil
+	}
+	existing, err := fetchDatabaseOptions(db, to)
+	if err != nil {
+		return nil, false, err
+	}
+	if existing != nil {
+		return nil, false, fmt.Errorf("cannot rename database %s to %s because both exist", from, to)
+	}
+	tables, err := fetchBaseTables(db, from)
+	if err != nil {
+		return nil, false, err
+	}
+	objects, err := fetchSchemaObjects(db, from)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to fetch remote schema objects : %w", err)
+	}
+
+	createDatabase := fmt.Sprintf("CREATE DATABASE `%s` DEFAULT CHARACTER SET %s COLLATE %s", to, options.CharacterSet, options.Collation)
+	if options.Encryption {
+		createDatabase += " DEFAULT ENCRYPTION 'Y'"
+	}
+	ret := []*plannedChange{}
+	// Tables cannot be moved out of a read-only database
+	if options.ReadOnly {
+		ret = append(ret, &plannedChange{
+			Sql:        fmt.Sprintf("ALTER SCHEMA `%s` READ ONLY = 0;", from),
+			Kind:       "alter",
+			ObjectType: "database",
+			Object:     from,
+			Reason:     fmt.Sprintf("database %s: made writable to be renamed to %s", from, to),
+		})
+	}
+	ret = append(ret, &plannedChange{
+		Sql:        createDatabase + ";",
+		Kind:       "create",
+		ObjectType: "database",
+		Object:     to,
+		Reason:     fmt.Sprintf("database %s: renamed from %s", to, from),
+	})
+	for _, o := range objects {
+		ret = append(ret, dropObjectChange(from, o, "alter", fmt.Sprintf("moved to database %s", to)))
+	}
+	for _, t := range tables {
+		ret = append(ret, &plannedChange{
+			Sql:        fmt.Sprintf("RENAME TABLE `%s`.`%s` TO `%s`.`%s`;", from, t, to, t),
+			Kind:       "rename",
+			ObjectType: "table",
+			Object:     t,
+			Reason:     fmt.Sprintf("table %s: moved to database %s", t, to),
+		})
+	}
+	for _, o := range objects {
+		definition := strings.ReplaceAll(o.Definition, fmt.Sprintf("`%s`.", from), fmt.Sprintf("`%s`.", to))
+		ret = append(ret, createObjectChange(o, definition, "alter", fmt.Sprintf("moved from database %s", from)))
+	}
+	ret = append(ret, &plannedChange{
+		Sql:        fmt.Sprintf("DROP DATABASE `%s`;", from),
+		Kind:       "drop",
+		ObjectType: "database",
+		Object:     from,
+		Reason:     fmt.Sprintf("database %s: renamed to %s", from, to),
+	})
+	return ret, options.ReadOnly, nil
+}
+
+// readOnlyChange returns the change to make the renamed database read only, as the old database was.
+func readOnlyChange(from string, to string) *plannedChange {
+	return &plannedChange{
+		Sql:        fmt.Sprintf("ALTER SCHEMA `%s` READ ONLY = 1;", to),
+		Kind:       "alter",
+		ObjectType: "database",
+		Object:     to,
+		Reason:     fmt.Sprintf("database %s: made read only like %s", to, from),
+	}
+}
+
+// renamedSchemaString returns the schema of the database named to, as if it were named from.
+func renamedSchemaString(client *cmd.Alternator, schemaStr string, to string, from string) (string, error) {
+	tableSchemaStr, objects := splitSchema(schemaStr)
+	// Read the schema as the database named to, since only the database of the client is read
+	uri := *client.DbUri
+	uri.DbName = to
+	reader := *client
+	reader.DbUri = &uri
+	schemas, err := reader.ReadSchemas(tableSchemaStr)
+	if err != nil {
+		return "", fmt.Errorf("failed to read local schema : %w", err)
+	}
+	if len(schemas) == 0 {
+		return "", fmt.Errorf("schema does not contain database %s", to)
+	}
+	return comparisonSchemaString(schemas[0], objects, to, from), nil
+}
+
+// renamedPlannedChanges returns copies of the changes of the database named from, whose statements are rewritten for the database named to.
+func renamedPlannedChanges(changes []*plannedChange, from string, to string) []*plannedChange {
+	ret := []*plannedChange{}
+	for _, c := range changes {
+		renamed := *c
+		renamed.Sql = strings.ReplaceAll(c.Sql, fmt.Sprintf("`%s`", from), fmt.Sprintf("`%s`", to))
+		ret = append(ret, &renamed)
+	}
+	return ret
+}
+
+func fetchBaseTables(db *sql.DB, database string) ([]string, error) {
+	rows, err := db.Query("SELECT TABLE_NAME FROM information_schema.TABLES WHERE TABLE_SCHEMA = ? AND TABLE_TYPE = 'BASE TABLE' ORDER BY TABLE_NAME", database)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables : %w", err)
+	}
+	defer rows.Close()
+	ret := []string{}
+	for rows.Next() {
+		var t string
+		err := rows.Scan(&t)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tables : %w", err)
+		}
+		ret = append(ret, t)
+	}
+	return ret, rows.Err()
+}
@@ -0,0 +1,192 @@
+package provider
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"io"
+	"os"
+	"strings"
+)
+
+// csvNull is the representation of NULL in CSV files, the same as `LOAD DATA` statement.
+const csvNull = `\N`
+
+type initialData struct {
+	SqlFile   string
+	CsvFile   string
+	Table     string
+	BatchSize int
+}
+
+func initialDataSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		Description: "Fixtures loaded in order only when the database is created. Changes to them are never applied to the existing database.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"sql_file": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "Path to a SQL file to execute. Multiple statements can be separated by `;`, and `DELIMITER` command is supported.",
+				},
+				"csv_file": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "Path to a CSV file to insert into `table`. The first line must be the column names, and `\\N` represents NULL.",
+				},
+				"table": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "Table to insert the rows of `csv_file` into.",
+				},
+				"batch_size": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Default:      1000,
+					Description:  "Number of rows of `csv_file` inserted by a single `INSERT` statement.",
+					ValidateFunc: validation.IntAtLeast(1),
+				},
+			},
+		},
+	}
+}
+
+func expandInitialData(v interface{}) []*initialData {
+	ret := []*initialData{}
+	for _, e := range v.([]interface{}) {
+		m := e.(map[string]interface{})
+		ret = append(ret, &initialData{
+			SqlFile:   m["sql_file"].(string),
+			CsvFile:   m["csv_file"].(string),
+			Table:     m["table"].(string),
+			BatchSize: m["batch_size"].(int),
+		})
+	}
+	return ret
+}
+
+func validateInitialData(data []*initialData) error {
+	for i, e := range data {
+		if (e.SqlFile == "") == (e.CsvFile == "") {
+			return fmt.Errorf("initial_data.%d must have exactly one of sql_file or csv_file", i)
+		}
+		if e.CsvFile != "" && e.Table == "" {
+			return fmt.Errorf("initial_data.%d must have table with csv_file", i)
+		}
+		if e.SqlFile != "" && e.Table != "" {
+			return fmt.Errorf("initial_data.%d cannot have table with sql_file", i)
+		}
+	}
+	return nil
+}
+
+// loadInitialData loads the fixtures into the database and returns the number of rows affected by each file.
+func loadInitialData(ctx context.Context, db *sql.DB, database string, data []*initialData) (map[string]int, error) {
+	ret := map[string]int{}
+	if len(data) == 0 {
+		return ret, nil
+	}
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	_, err = conn.ExecContext(ctx, fmt.Sprintf("USE `%s`", database))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, e := range data {
+		var statements []string
+		file := e.SqlFile
+		if file != "" {
+			b, err := os.ReadFile(file)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s : %w", file, err)
+			}
+			statements = splitStatements(string(b))
+		} else {
+			file = e.CsvFile
+			f, err := os.Open(file)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s : %w", file, err)
+			}
+			statements, err = csvInsertStatements(f, e.Table, e.BatchSize)
+			f.Close()
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s : %w", file, err)
+			}
+		}
+
+		count := 0
+		for _, s := range statements {
+			tflog.Info(ctx, fmt.Sprintf("@create executing statements: %s", truncate(s, 200)))
+			res, err := conn.ExecContext(ctx, s)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load %s : %w", file, err)
+			}
+			n, err := res.RowsAffected()
+			if err == nil {
+				count += int(n)
+			}
+		}
+		ret[file] += count
+	}
+	return ret, nil
+}
+
+// csvInsertStatements returns INSERT statements inserting the rows of the CSV, each of which has at most batchSize rows.
+func csvInsertStatements(r io.Reader, table string, batchSize int) ([]string, error) {
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	prefix := fmt.Sprintf("INSERT INTO `%s` (%s) VALUES ", table, quoteColumns(header))
+
+	ret := []string{}
+	values := []string{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		quoted := []string{}
+		for _, v := range record {
+			if v == csvNull {
+				quoted = append(quoted, "NULL")
+			} else {
+				quoted = append(quoted, quoteString(v))
+			}
+		}
+		values = append(values, fmt.Sprintf("(%s)", strings.Join(quoted, ", ")))
+		if len(values) == batchSize {
+			ret = append(ret, prefix+strings.Join(values, ", "))
+			values = []string{}
+		}
+	}
+	if len(values) > 0 {
+		ret = append(ret, prefix+strings.Join(values, ", "))
+	}
+	return ret, nil
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
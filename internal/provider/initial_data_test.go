@@ -0,0 +1,29 @@
+package provider
+
+import (
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestCsvInsertStatements(t *testing.T) {
+	csv := "id,body\n1,hello\n2,\\N\n3,\"it's, fine\"\n"
+	statements, err := csvInsertStatements(strings.NewReader(csv), "greeting", 2)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"INSERT INTO `greeting` (`id`, `body`) VALUES ('1', 'hello'), ('2', NULL)",
+		"INSERT INTO `greeting` (`id`, `body`) VALUES ('3', 'it''s, fine')",
+	}, statements)
+
+	statements, err = csvInsertStatements(strings.NewReader(""), "greeting", 2)
+	require.NoError(t, err)
+	require.Empty(t, statements)
+}
+
+func TestValidateInitialData(t *testing.T) {
+	require.NoError(t, validateInitialData([]*initialData{{SqlFile: "a.sql"}, {CsvFile: "a.csv", Table: "a"}}))
+	require.Error(t, validateInitialData([]*initialData{{SqlFile: "a.sql", CsvFile: "a.csv"}}))
+	require.Error(t, validateInitialData([]*initialData{{}}))
+	require.Error(t, validateInitialData([]*initialData{{CsvFile: "a.csv"}}))
+	require.Error(t, validateInitialData([]*initialData{{SqlFile: "a.sql", Table: "a"}}))
+}
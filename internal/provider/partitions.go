@@ -0,0 +1,173 @@
+package provider
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/kota65535/alternator/lib"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// tablePartition is a partition of a table, as shown in information_schema.PARTITIONS.
+type tablePartition struct {
+	Name        string
+	Method      string
+	Expression  string
+	Description string
+}
+
+// partitionIntervals are the supported intervals of partition rotation, and the formats of partition names.
+var partitionIntervals = map[string]string{
+	"day":   "p20060102",
+	"month": "p200601",
+}
+
+// ignorePartitionDefinitions copies the partition definitions of the remote tables to the local ones, if they are partitioned in the same way.
+func ignorePartitionDefinitions(remoteSchemas []*lib.Schema, localSchemas []*lib.Schema, tables []string) {
+	for _, l := range localSchemas {
+		for _, r := range remoteSchemas {
+			if l.Database.DbName != r.Database.DbName {
+				continue
+			}
+			for _, name := range tables {
+				lt := findTable(l, name)
+				rt := findTable(r, name)
+				if lt == nil || rt == nil || lt.Partitions.PartitionBy.String() != rt.Partitions.PartitionBy.String() {
+					continue
+				}
+				lt.Partitions.PartitionDefinitions = rt.Partitions.PartitionDefinitions
+			}
+		}
+	}
+}
+
+// fetchTablePartitions returns the partitions of the table in order. It returns empty if the table is not partitioned.
+func fetchTablePartitions(db *sql.DB, database string, table string) ([]*tablePartition, error) {
+	rows, err := db.Query("SELECT PARTITION_NAME, PARTITION_METHOD, PARTITION_EXPRESSION, PARTITION_DESCRIPTION FROM information_schema.PARTITIONS "+
+		"WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND PARTITION_NAME IS NOT NULL ORDER BY PARTITION_ORDINAL_POSITION", database, table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query partitions of %s : %w", table, err)
+	}
+	defer rows.Close()
+	ret := []*tablePartition{}
+	for rows.Next() {
+		p := &tablePartition{}
+		var expression, description sql.NullString
+		err := rows.Scan(&p.Name, &p.Method, &expression, &description)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query partitions of %s : %w", table, err)
+		}
+		p.Expression = expression.String
+		p.Description = description.String
+		ret = append(ret, p)
+	}
+	return ret, rows.Err()
+}
+
+// partitionRotationStatements returns statements to add the partitions from the current period to premake periods ahead,
+// and to drop the partitions older than retention periods before the current one. retention 0 means keeping all.
+// Only partitions named in the format of the interval are regarded as managed ones.
+func partitionRotationStatements(database string, table string, column string, partitions []*tablePartition,
+	interval string, premake int, retention int, now time.Time) ([]string, error) {
+	if len(partitions) == 0 {
+		return nil, fmt.Errorf("table %s.%s is not partitioned", database, table)
+	}
+	if !strings.HasPrefix(partitions[0].Method, "RANGE") {
+		return nil, fmt.Errorf("table %s.%s is partitioned by %s, but only RANGE partitioning is supported", database, table, partitions[0].Method)
+	}
+	expression := partitions[0].Expression
+	if !partitionColumnPattern(column).MatchString(expression) {
+		return nil, fmt.Errorf("partition expression %s of table %s.%s does not contain column %s", expression, database, table, column)
+	}
+
+	current := truncatePeriod(now, interval)
+	var latest time.Time
+	var maxValue *tablePartition
+	expired := []string{}
+	for _, p := range partitions {
+		if p.Description == "MAXVALUE" {
+			maxValue = p
+			continue
+		}
+		start, ok := parsePartitionName(p.Name, interval)
+		if !ok {
+			continue
+		}
+		if start.After(latest) {
+			latest = start
+		}
+		if retention > 0 && start.Before(addPeriods(current, interval, -retention)) {
+			expired = append(expired, fmt.Sprintf("`%s`", p.Name))
+		}
+	}
+
+	added := []string{}
+	for i := 0; i <= premake; i++ {
+		start := addPeriods(current, interval, i)
+		if !latest.IsZero() && !start.After(latest) {
+			continue
+		}
+		end := addPeriods(start, interval, 1)
+		added = append(added, fmt.Sprintf("PARTITION `%s` VALUES LESS THAN (%s)",
+			start.Format(partitionIntervals[interval]), partitionBoundary(expression, column, end)))
+	}
+
+	ret := []string{}
+	if len(added) > 0 {
+		if maxValue == nil {
+			ret = append(ret, fmt.Sprintf("ALTER TABLE `%s`.`%s` ADD PARTITION (%s)", database, table, strings.Join(added, ", ")))
+		} else {
+			// Partitions cannot be added after the MAXVALUE partition, so it is split instead
+			added = append(added, fmt.Sprintf("PARTITION `%s` VALUES LESS THAN MAXVALUE", maxValue.Name))
+			ret = append(ret, fmt.Sprintf("ALTER TABLE `%s`.`%s` REORGANIZE PARTITION `%s` INTO (%s)", database, table, maxValue.Name, strings.Join(added, ", ")))
+		}
+	}
+	if len(expired) > 0 {
+		ret = append(ret, fmt.Sprintf("ALTER TABLE `%s`.`%s` DROP PARTITION %s", database, table, strings.Join(expired, ", ")))
+	}
+	return ret, nil
+}
+
+// partitionBoundary returns the value of VALUES LESS THAN clause, by substituting the date into the column of the partition expression.
+// e.g. to_days(`created_at`) -> to_days('2024-01-02')
+func partitionBoundary(expression string, column string, t time.Time) string {
+	literal := fmt.Sprintf("'%s'", t.Format("2006-01-02"))
+	return partitionColumnPattern(column).ReplaceAllLiteralString(expression, literal)
+}
+
+// partitionColumnPattern matches the column in the partition expression, either quoted or not.
+// Only the whole identifier is matched, e.g. created_at in to_days(created_at) but not in to_days(created_at_utc)
+func partitionColumnPattern(column string) *regexp.Regexp {
+	quoted := regexp.QuoteMeta(column)
+	return regexp.MustCompile(fmt.Sprintf("`%s`|\\b%s\\b", quoted, quoted))
+}
+
+func parsePartitionName(name string, interval string) (time.Time, bool) {
+	t, err := time.Parse(partitionIntervals[interval], name)
+	return t, err == nil
+}
+
+func truncatePeriod(t time.Time, interval string) time.Time {
+	if interval == "month" {
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func addPeriods(t time.Time, interval string, n int) time.Time {
+	if interval == "month" {
+		return t.AddDate(0, n, 0)
+	}
+	return t.AddDate(0, 0, n)
+}
+
+func partitionIntervalNames() []string {
+	ret := []string{}
+	for k := range partitionIntervals {
+		ret = append(ret, k)
+	}
+	sort.Strings(ret)
+	return ret
+}
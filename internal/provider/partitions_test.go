@@ -0,0 +1,108 @@
+package provider
+
+import (
+	"github.com/kota65535/alternator/lib"
+	"github.com/kota65535/alternator/parser"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestPartitionRotationStatements(t *testing.T) {
+	now := time.Date(2024, 3, 10, 15, 0, 0, 0, time.UTC)
+	partitions := []*tablePartition{
+		{Name: "p20240307", Method: "RANGE", Expression: "to_days(`created_at`)", Description: "739318"},
+		{Name: "p20240308", Method: "RANGE", Expression: "to_days(`created_at`)", Description: "739319"},
+		{Name: "p20240309", Method: "RANGE", Expression: "to_days(`created_at`)", Description: "739320"},
+		{Name: "p20240310", Method: "RANGE", Expression: "to_days(`created_at`)", Description: "739321"},
+	}
+	statements, err := partitionRotationStatements("example", "events", "created_at", partitions, "day", 2, 1, now)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"ALTER TABLE `example`.`events` ADD PARTITION (" +
+			"PARTITION `p20240311` VALUES LESS THAN (to_days('2024-03-12')), " +
+			"PARTITION `p20240312` VALUES LESS THAN (to_days('2024-03-13')))",
+		"ALTER TABLE `example`.`events` DROP PARTITION `p20240307`, `p20240308`",
+	}, statements)
+
+	// Nothing to do
+	statements, err = partitionRotationStatements("example", "events", "created_at", partitions, "day", 0, 0, now)
+	require.NoError(t, err)
+	require.Empty(t, statements)
+}
+
+func TestPartitionRotationStatementsMaxValue(t *testing.T) {
+	now := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+	partitions := []*tablePartition{
+		{Name: "p0", Method: "RANGE COLUMNS", Expression: "`created_at`", Description: "'2024-01-01'"},
+		{Name: "pmax", Method: "RANGE COLUMNS", Expression: "`created_at`", Description: "MAXVALUE"},
+	}
+	statements, err := partitionRotationStatements("example", "events", "created_at", partitions, "month", 1, 0, now)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"ALTER TABLE `example`.`events` REORGANIZE PARTITION `pmax` INTO (" +
+			"PARTITION `p202403` VALUES LESS THAN ('2024-04-01'), " +
+			"PARTITION `p202404` VALUES LESS THAN ('2024-05-01'), " +
+			"PARTITION `pmax` VALUES LESS THAN MAXVALUE)",
+	}, statements)
+}
+
+func TestPartitionRotationStatementsError(t *testing.T) {
+	now := time.Now()
+	_, err := partitionRotationStatements("example", "events", "created_at", []*tablePartition{}, "day", 1, 0, now)
+	require.Error(t, err)
+	_, err = partitionRotationStatements("example", "events", "created_at", []*tablePartition{
+		{Name: "p0", Method: "HASH", Expression: "`id`"},
+	}, "day", 1, 0, now)
+	require.Error(t, err)
+	_, err = partitionRotationStatements("example", "events", "created_at", []*tablePartition{
+		{Name: "p0", Method: "RANGE", Expression: "`id`", Description: "100"},
+	}, "day", 1, 0, now)
+	require.Error(t, err)
+	// Column name is a part of another column name
+	_, err = partitionRotationStatements("example", "events", "created", []*tablePartition{
+		{Name: "p20240101", Method: "RANGE", Expression: "to_days(created_at)", Description: "739252"},
+	}, "day", 1, 0, now)
+	require.Error(t, err)
+	_, err = partitionRotationStatements("example", "events", "created", []*tablePartition{
+		{Name: "p20240101", Method: "RANGE", Expression: "to_days(`created_at`)", Description: "739252"},
+	}, "day", 1, 0, now)
+	require.Error(t, err)
+}
+
+func TestPartitionBoundary(t *testing.T) {
+	d := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	require.Equal(t, "to_days('2024-01-02')", partitionBoundary("to_days(`created_at`)", "created_at", d))
+	require.Equal(t, "to_days('2024-01-02')", partitionBoundary("to_days(created_at)", "created_at", d))
+	require.Equal(t, "to_days(created_at_utc) + to_days('2024-01-02')", partitionBoundary("to_days(created_at_utc) + to_days(created_at)", "created_at", d))
+	require.Equal(t, "unix_timestamp('2024-01-02')", partitionBoundary("unix_timestamp(at)", "at", d))
+}
+
+func TestIgnorePartitionDefinitions(t *testing.T) {
+	newSchema := func(partitionBy string, names ...string) *lib.Schema {
+		definitions := []parser.PartitionDefinition{}
+		for _, n := range names {
+			definitions = append(definitions, parser.PartitionDefinition{Name: n, Operator: "LESS THAN"})
+		}
+		return &lib.Schema{
+			Database: &parser.CreateDatabaseStatement{DbName: "example"},
+			Tables: []*parser.CreateTableStatement{{
+				TableName: "events",
+				Partitions: parser.PartitionConfig{
+					PartitionBy:          parser.PartitionBy{Type: "RANGE", Expression: partitionBy},
+					PartitionDefinitions: definitions,
+				},
+			}},
+		}
+	}
+
+	remote := newSchema("to_days(`created_at`)", "p20240101", "p20240102")
+	local := newSchema("to_days(`created_at`)", "p0")
+	ignorePartitionDefinitions([]*lib.Schema{remote}, []*lib.Schema{local}, []string{"events"})
+	require.Equal(t, remote.Tables[0].Partitions.PartitionDefinitions, local.Tables[0].Partitions.PartitionDefinitions)
+
+	// Partitioning method is changed
+	local = newSchema("to_days(`updated_at`)", "p0")
+	ignorePartitionDefinitions([]*lib.Schema{remote}, []*lib.Schema{local}, []string{"events"})
+	require.Len(t, local.Tables[0].Partitions.PartitionDefinitions, 1)
+}
@@ -0,0 +1,510 @@
+package provider
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/kota65535/alternator/lib"
+	"github.com/kota65535/alternator/parser"
+	"strings"
+)
+
+// plannedChange is a single statement to execute, annotated with what it changes and why.
+type plannedChange struct {
+	Sql             string
+	Kind            string
+	ObjectType      string
+	Object          string
+	Destructive     bool
+	RequiresRebuild bool
+	Reason          string
+
+	// alteration is the source of the statement. One alteration may produce multiple statements.
+	alteration lib.Alteration
+	// schemaObject is the source of the statement instead of alteration, if it changes a schema object other than tables.
+	schemaObject *schemaObject
+}
+
+// Order of the object types shown in change summaries, with their plural forms.
+var objectTypePlurals = []struct {
+	singular string
+	plural   string
+}{
+	{"database", "databases"},
+	{"table", "tables"},
+	{"table option", "table options"},
+	{"column", "columns"},
+	{"primary key", "primary keys"},
+	{"unique key", "unique keys"},
+	{"index", "indexes"},
+	{"fulltext index", "fulltext indexes"},
+	{"foreign key", "foreign keys"},
+	{"check constraint", "check constraints"},
+	{"view", "views"},
+	{"trigger", "triggers"},
+	{"procedure", "procedures"},
+	{"function", "functions"},
+	{"event", "events"},
+}
+
+// Table options that make MySQL rebuild the whole table when changed
+var rebuildingTableOptions = []string{"ENGINE", "ROW_FORMAT", "KEY_BLOCK_SIZE", "COMPRESSION"}
+
+func plannedChangesSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Computed:    true,
+		Description: "Structured statements to execute on apply.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"sql": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "SQL statement.",
+				},
+				"kind": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "Kind of the change. One of `create`, `alter`, `drop` or `rename`.",
+				},
+				"object": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "Name of the changed object. Columns and indexes are qualified by their table name.",
+				},
+				"destructive": {
+					Type:        schema.TypeBool,
+					Computed:    true,
+					Description: "Whether the statement drops existing data.",
+				},
+				"requires_rebuild": {
+					Type:        schema.TypeBool,
+					Computed:    true,
+					Description: "Whether the statement is likely to rebuild the whole table.",
+				},
+				"reason": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "Human-readable description of the change.",
+				},
+			},
+		},
+	}
+}
+
+func flattenPlannedChanges(changes []*plannedChange) []interface{} {
+	ret := []interface{}{}
+	for _, c := range changes {
+		ret = append(ret, map[string]interface{}{
+			"sql":              c.Sql,
+			"kind":             c.Kind,
+			"object":           c.Object,
+			"destructive":      c.Destructive,
+			"requires_rebuild": c.RequiresRebuild,
+			"reason":           c.Reason,
+		})
+	}
+	return ret
+}
+
+// changeSummary returns a short summary of changes like "+1 table, ~2 columns, -1 index".
+func changeSummary(changes []*plannedChange) string {
+	if len(changes) == 0 {
+		return "no changes"
+	}
+	counts := map[string]int{}
+	seen := map[interface{}]bool{}
+	for _, c := range changes {
+		if source := c.source(); source != nil {
+			if seen[source] {
+				continue
+			}
+			seen[source] = true
+		}
+		counts[changeSymbol(c.Kind)+c.ObjectType] += 1
+	}
+	ret := []string{}
+	for _, symbol := range []string{"+", "~", "-"} {
+		for _, t := range objectTypePlurals {
+			n := counts[symbol+t.singular]
+			if n == 0 {
+				continue
+			}
+			name := t.plural
+			if n == 1 {
+				name = t.singular
+			}
+			ret = append(ret, fmt.Sprintf("%s%d %s", symbol, n, name))
+		}
+	}
+	return strings.Join(ret, ", ")
+}
+
+// source returns what the change is made from, so that changes made from the same source are counted once.
+func (c *plannedChange) source() interface{} {
+	if c.alteration != nil {
+		return c.alteration
+	}
+	if c.schemaObject != nil {
+		return c.schemaObject
+	}
+	return nil
+}
+
+func changeSymbol(kind string) string {
+	switch kind {
+	case "create":
+		return "+"
+	case "drop":
+		return "-"
+	default:
+		return "~"
+	}
+}
+
+// plannedChanges builds structured changes from database alterations.
+// Statements are generated in the same order and format as DatabaseAlterations.Statements().
+func plannedChanges(alt *lib.DatabaseAlterations) []*plannedChange {
+	ret := []*plannedChange{}
+	for _, a := range alt.Alterations() {
+		switch v := a.(type) {
+		case *lib.AddedDatabase:
+			ret = append(ret, &plannedChange{
+				Sql:        v.This.String(),
+				Kind:       "create",
+				ObjectType: "database",
+				Object:     v.This.DbName,
+				Reason:     fmt.Sprintf("database %s: created", v.This.DbName),
+				alteration: v,
+			})
+			ret = append(ret, tablePlannedChanges(v.Tables)...)
+		case *lib.ModifiedDatabase:
+			for _, s := range v.DbOptions.Statements() {
+				ret = append(ret, &plannedChange{
+					Sql:        fmt.Sprintf("ALTER DATABASE `%s` %s;", v.From.DbName, s),
+					Kind:       "alter",
+					ObjectType: "database",
+					Object:     v.From.DbName,
+					Reason:     fmt.Sprintf("database %s: %s", v.From.DbName, s),
+					alteration: v,
+				})
+			}
+			ret = append(ret, tablePlannedChanges(v.Tables)...)
+		case *lib.DroppedDatabase:
+			ret = append(ret, &plannedChange{
+				Sql:         fmt.Sprintf("DROP DATABASE `%s`;", v.This.DbName),
+				Kind:        "drop",
+				ObjectType:  "database",
+				Object:      v.This.DbName,
+				Destructive: true,
+				Reason:      fmt.Sprintf("database %s: dropped", v.This.DbName),
+				alteration:  v,
+			})
+		case *lib.RetainedDatabase:
+			ret = append(ret, tablePlannedChanges(&v.Tables)...)
+		}
+	}
+	return ret
+}
+
+func tablePlannedChanges(tables *lib.TableAlterations) []*plannedChange {
+	// Table element alterations must be sorted before collecting table names,
+	// because ModifiedTable.Alterations() updates sequential numbers of its elements.
+	elements := tables.TableElementAlterations()
+
+	tableNames := map[lib.Alteration]string{}
+	for _, t := range tables.Added {
+		for _, a := range t.Alterations() {
+			tableNames[a] = t.This.TableName
+		}
+	}
+	for _, t := range tables.Modified {
+		for _, a := range t.Alterations() {
+			tableNames[a] = t.To.TableName
+		}
+	}
+	for _, t := range tables.Dropped {
+		for _, a := range t.Alterations() {
+			tableNames[a] = t.This.TableName
+		}
+	}
+	for _, t := range tables.Renamed {
+		for _, a := range t.Alterations() {
+			tableNames[a] = t.To.TableName
+		}
+	}
+	for _, t := range tables.Retained {
+		for _, a := range t.Alterations() {
+			tableNames[a] = t.This.TableName
+		}
+	}
+
+	ret := []*plannedChange{}
+	for _, a := range elements {
+		raw := a.Statements()
+		statements := []string{}
+		for _, s := range raw {
+			statements = append(statements, a.Prefix()+s)
+		}
+		statements = parser.Align(statements)
+		for i, s := range statements {
+			if !strings.HasSuffix(s, ";") {
+				s += ";"
+			}
+			c := describeAlteration(a, tableNames[a], raw[i])
+			c.Sql = s
+			c.alteration = a
+			ret = append(ret, c)
+		}
+	}
+	return ret
+}
+
+// describeAlteration classifies a table element alteration.
+// The statement is the one generated by the alteration, without the ALTER TABLE prefix.
+func describeAlteration(a lib.Alteration, table string, statement string) *plannedChange {
+	qualified := func(name string) string {
+		return fmt.Sprintf("%s.%s", table, name)
+	}
+	switch v := a.(type) {
+	// Tables
+	case *lib.AddedTable:
+		return &plannedChange{
+			Kind:       "create",
+			ObjectType: "table",
+			Object:     table,
+			Reason:     fmt.Sprintf("table %s: created", table),
+		}
+	case *lib.DroppedTable:
+		return &plannedChange{
+			Kind:        "drop",
+			ObjectType:  "table",
+			Object:      table,
+			Destructive: true,
+			Reason:      fmt.Sprintf("table %s: dropped", table),
+		}
+	case *lib.RenamedTable:
+		return &plannedChange{
+			Kind:       "rename",
+			ObjectType: "table",
+			Object:     table,
+			Reason:     fmt.Sprintf("table %s: renamed to %s", v.From.TableName, v.To.TableName),
+		}
+	case *lib.TableOptionAlterations:
+		rebuild := false
+		for _, o := range rebuildingTableOptions {
+			if strings.HasPrefix(statement, o+" ") {
+				rebuild = true
+			}
+		}
+		return &plannedChange{
+			Kind:            "alter",
+			ObjectType:      "table option",
+			Object:          table,
+			RequiresRebuild: rebuild,
+			Reason:          fmt.Sprintf("table %s: %s", table, statement),
+		}
+
+	// Columns
+	case *lib.AddedColumn:
+		return &plannedChange{
+			Kind:       "create",
+			ObjectType: "column",
+			Object:     qualified(v.This.ColumnName),
+			Reason:     fmt.Sprintf("column %s: added as %s", v.This.ColumnName, columnSpec(v.This)),
+		}
+	case *lib.ModifiedColumn:
+		return &plannedChange{
+			Kind:            "alter",
+			ObjectType:      "column",
+			Object:          qualified(v.To.ColumnName),
+			RequiresRebuild: fmt.Sprint(v.From.DataType) != fmt.Sprint(v.To.DataType),
+			Reason:          fmt.Sprintf("column %s: %s -> %s", v.To.ColumnName, columnSpec(v.From), columnSpec(v.To)),
+		}
+	case *lib.MovedColumn:
+		position := "first"
+		if v.After != nil && v.After.ColumnName != "" {
+			position = fmt.Sprintf("after %s", v.After.ColumnName)
+		}
+		return &plannedChange{
+			Kind:            "alter",
+			ObjectType:      "column",
+			Object:          qualified(v.To.ColumnName),
+			RequiresRebuild: true,
+			Reason:          fmt.Sprintf("column %s: moved %s", v.To.ColumnName, position),
+		}
+	case *lib.DroppedColumn:
+		return &plannedChange{
+			Kind:            "drop",
+			ObjectType:      "column",
+			Object:          qualified(v.This.ColumnName),
+			Destructive:     true,
+			RequiresRebuild: true,
+			Reason:          fmt.Sprintf("column %s: dropped", v.This.ColumnName),
+		}
+	case *lib.RenamedColumn:
+		return &plannedChange{
+			Kind:       "rename",
+			ObjectType: "column",
+			Object:     qualified(v.To.ColumnName),
+			Reason:     fmt.Sprintf("column %s: renamed to %s", v.From.ColumnName, v.To.ColumnName),
+		}
+
+	// Primary keys
+	case *lib.AddedPrimaryKey:
+		return &plannedChange{
+			Kind:            "create",
+			ObjectType:      "primary key",
+			Object:          qualified("PRIMARY"),
+			RequiresRebuild: true,
+			Reason:          fmt.Sprintf("primary key: added on %s", keyPartsString(v.This.KeyPartList)),
+		}
+	case *lib.ModifiedPrimaryKey:
+		return &plannedChange{
+			Kind:            "alter",
+			ObjectType:      "primary key",
+			Object:          qualified("PRIMARY"),
+			RequiresRebuild: true,
+			Reason:          fmt.Sprintf("primary key: %s -> %s", keyPartsString(v.From.KeyPartList), keyPartsString(v.To.KeyPartList)),
+		}
+	case *lib.DroppedPrimaryKey:
+		return &plannedChange{
+			Kind:            "drop",
+			ObjectType:      "primary key",
+			Object:          qualified("PRIMARY"),
+			RequiresRebuild: true,
+			Reason:          "primary key: dropped",
+		}
+
+	// Unique keys
+	case *lib.AddedUniqueKey:
+		return addedIndexChange("unique key", qualified(v.This.IndexName), v.This.IndexName, v.This.KeyPartList)
+	case *lib.ModifiedUniqueKey:
+		return modifiedIndexChange("unique key", qualified(v.To.IndexName), v.To.IndexName)
+	case *lib.DroppedUniqueKey:
+		return droppedIndexChange("unique key", qualified(v.This.IndexName), v.This.IndexName)
+	case *lib.RenamedUniqueKey:
+		return renamedIndexChange("unique key", qualified(v.To.IndexName), v.From.IndexName, v.To.IndexName)
+
+	// Indexes
+	case *lib.AddedIndex:
+		return addedIndexChange("index", qualified(v.This.IndexName), v.This.IndexName, v.This.KeyPartList)
+	case *lib.ModifiedIndex:
+		return modifiedIndexChange("index", qualified(v.To.IndexName), v.To.IndexName)
+	case *lib.DroppedIndex:
+		return droppedIndexChange("index", qualified(v.This.IndexName), v.This.IndexName)
+	case *lib.RenamedIndex:
+		return renamedIndexChange("index", qualified(v.To.IndexName), v.From.IndexName, v.To.IndexName)
+
+	// Fulltext indexes
+	case *lib.AddedFullTextIndex:
+		return addedIndexChange("fulltext index", qualified(v.This.IndexName), v.This.IndexName, v.This.KeyPartList)
+	case *lib.ModifiedFullTextIndex:
+		return modifiedIndexChange("fulltext index", qualified(v.To.IndexName), v.To.IndexName)
+	case *lib.DroppedFullTextIndex:
+		return droppedIndexChange("fulltext index", qualified(v.This.IndexName), v.This.IndexName)
+	case *lib.RenamedFullTextIndex:
+		return renamedIndexChange("fulltext index", qualified(v.To.IndexName), v.From.IndexName, v.To.IndexName)
+
+	// Foreign keys
+	case *lib.AddedForeignKey:
+		name := foreignKeyName(v.This)
+		return &plannedChange{
+			Kind:       "create",
+			ObjectType: "foreign key",
+			Object:     qualified(name),
+			Reason: fmt.Sprintf("foreign key %s: added on %s referencing %s %s",
+				name, keyPartsString(v.This.KeyPartList), v.This.ReferenceDefinition.TableName, keyPartsString(v.This.ReferenceDefinition.KeyPartList)),
+		}
+	case *lib.DroppedForeignKey:
+		name := foreignKeyName(v.This)
+		return &plannedChange{
+			Kind:       "drop",
+			ObjectType: "foreign key",
+			Object:     qualified(name),
+			Reason:     fmt.Sprintf("foreign key %s: dropped", name),
+		}
+
+	// Check constraints
+	case *lib.AddedCheckConstraint:
+		return &plannedChange{
+			Kind:       "create",
+			ObjectType: "check constraint",
+			Object:     qualified(v.This.ConstraintName),
+			Reason:     fmt.Sprintf("check constraint %s: added (%s)", v.This.ConstraintName, v.This.Check),
+		}
+	case *lib.ModifiedCheckConstraint:
+		return &plannedChange{
+			Kind:       "alter",
+			ObjectType: "check constraint",
+			Object:     qualified(v.To.ConstraintName),
+			Reason:     fmt.Sprintf("check constraint %s: %s", v.To.ConstraintName, statement),
+		}
+	case *lib.DroppedCheckConstraint:
+		return &plannedChange{
+			Kind:       "drop",
+			ObjectType: "check constraint",
+			Object:     qualified(v.This.ConstraintName),
+			Reason:     fmt.Sprintf("check constraint %s: dropped", v.This.ConstraintName),
+		}
+	}
+
+	// Fallback for alterations without dedicated description
+	return &plannedChange{
+		Kind:       "alter",
+		ObjectType: "table",
+		Object:     table,
+		Reason:     fmt.Sprintf("table %s: %s", table, statement),
+	}
+}
+
+func addedIndexChange(objectType string, object string, name string, keyParts []parser.KeyPart) *plannedChange {
+	return &plannedChange{
+		Kind:       "create",
+		ObjectType: objectType,
+		Object:     object,
+		Reason:     fmt.Sprintf("%s %s: added on %s", objectType, name, keyPartsString(keyParts)),
+	}
+}
+
+func modifiedIndexChange(objectType string, object string, name string) *plannedChange {
+	return &plannedChange{
+		Kind:       "alter",
+		ObjectType: objectType,
+		Object:     object,
+		Reason:     fmt.Sprintf("%s %s: options changed", objectType, name),
+	}
+}
+
+func droppedIndexChange(objectType string, object string, name string) *plannedChange {
+	return &plannedChange{
+		Kind:       "drop",
+		ObjectType: objectType,
+		Object:     object,
+		Reason:     fmt.Sprintf("%s %s: dropped", objectType, name),
+	}
+}
+
+func renamedIndexChange(objectType string, object string, from string, to string) *plannedChange {
+	return &plannedChange{
+		Kind:       "rename",
+		ObjectType: objectType,
+		Object:     object,
+		Reason:     fmt.Sprintf("%s %s: renamed to %s", objectType, from, to),
+	}
+}
+
+func foreignKeyName(f *parser.ForeignKeyDefinition) string {
+	if f.ConstraintName != "" {
+		return f.ConstraintName
+	}
+	return f.IndexName
+}
+
+// columnSpec returns column definition without column name, like "varchar(256) NOT NULL".
+func columnSpec(c *parser.ColumnDefinition) string {
+	return strings.TrimSpace(fmt.Sprintf("%s %s", c.DataType, c.ColumnOptions.String()))
+}
+
+func keyPartsString(keyParts []parser.KeyPart) string {
+	return fmt.Sprintf("(%s)", strings.Join(keyPartNames(keyParts), ", "))
+}
@@ -0,0 +1,96 @@
+package provider
+
+import (
+	"github.com/emirpasic/gods/sets/hashset"
+	"github.com/kota65535/alternator/lib"
+	"github.com/kota65535/alternator/parser"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+var testGlobalConfig = &parser.GlobalConfig{
+	CharacterSetServer:   "utf8mb4",
+	CharacterSetDatabase: "utf8mb4",
+	CollationServer:      "utf8mb4_0900_ai_ci",
+	CharsetToCollation:   map[string]string{"utf8mb4": "utf8mb4_0900_ai_ci"},
+	Encryption:           "'N'",
+}
+
+func newTestSchemas(t *testing.T, str string) []*lib.Schema {
+	schemas, err := lib.NewSchemas(str, testGlobalConfig, hashset.New())
+	require.NoError(t, err)
+	return schemas
+}
+
+func TestPlannedChanges(t *testing.T) {
+	from := newTestSchemas(t, `
+CREATE DATABASE example;
+USE example;
+CREATE TABLE greeting
+(
+    id   int AUTO_INCREMENT,
+    body varchar(200),
+    note text,
+    PRIMARY KEY (id)
+);
+CREATE TABLE obsolete
+(
+    id int
+);
+`)
+	to := newTestSchemas(t, `
+CREATE DATABASE example;
+USE example;
+CREATE TABLE greeting
+(
+    id   int AUTO_INCREMENT,
+    body varchar(256),
+    PRIMARY KEY (id)
+);
+CREATE TABLE author
+(
+    id   int,
+    name varchar(100)
+);
+`)
+	changes := plannedChanges(lib.NewDatabaseAlterations(from, to))
+
+	alt := lib.NewDatabaseAlterations(from, to)
+	statements := alt.Statements()
+	require.Len(t, changes, len(statements))
+	for i, c := range changes {
+		require.Equal(t, statements[i], c.Sql)
+	}
+
+	byReason := map[string]*plannedChange{}
+	for _, c := range changes {
+		byReason[c.Reason] = c
+	}
+
+	c := byReason["column body: varchar(200) -> varchar(256)"]
+	require.NotNil(t, c)
+	require.Equal(t, "alter", c.Kind)
+	require.Equal(t, "greeting.body", c.Object)
+	require.False(t, c.Destructive)
+	require.True(t, c.RequiresRebuild)
+
+	c = byReason["column note: dropped"]
+	require.NotNil(t, c)
+	require.Equal(t, "drop", c.Kind)
+	require.True(t, c.Destructive)
+
+	c = byReason["table author: created"]
+	require.NotNil(t, c)
+	require.Equal(t, "create", c.Kind)
+	require.Equal(t, "author", c.Object)
+
+	c = byReason["table obsolete: dropped"]
+	require.NotNil(t, c)
+	require.True(t, c.Destructive)
+
+	require.Equal(t, "+1 table, ~1 column, -1 table, -1 column", changeSummary(changes))
+}
+
+func TestChangeSummaryNoChanges(t *testing.T) {
+	require.Equal(t, "no changes", changeSummary([]*plannedChange{}))
+}
@@ -61,10 +61,28 @@ func New() *schema.Provider {
 			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"alternator_database_schema": resourceAlternatorDatabaseSchema(),
+			"alternator_charset_migration":  resourceAlternatorCharsetMigration(),
+			"alternator_data_migration":     resourceAlternatorDataMigration(),
+			"alternator_database":           resourceAlternatorDatabase(),
+			"alternator_database_clone":     resourceAlternatorDatabaseClone(),
+			"alternator_database_schema":    resourceAlternatorDatabaseSchema(),
+			"alternator_grant":              resourceAlternatorGrant(),
+			"alternator_partition_rotation": resourceAlternatorPartitionRotation(),
+			"alternator_role":               resourceAlternatorRole(),
+			"alternator_server_variables":   resourceAlternatorServerVariables(),
+			"alternator_sql":                resourceAlternatorSql(),
+			"alternator_table":              resourceAlternatorTable(),
+			"alternator_table_rows":         resourceAlternatorTableRows(),
+			"alternator_user":               resourceAlternatorUser(),
 		},
 		DataSourcesMap: map[string]*schema.Resource{
-			"alternator_database_schema": dataSourceAlternatorDatabaseSchema(),
+			"alternator_database_comparison": dataSourceAlternatorDatabaseComparison(),
+			"alternator_database_schema":     dataSourceAlternatorDatabaseSchema(),
+			"alternator_databases":           dataSourceAlternatorDatabases(),
+			"alternator_query":               dataSourceAlternatorQuery(),
+			"alternator_schema_diff":         dataSourceAlternatorSchemaDiff(),
+			"alternator_server_info":         dataSourceAlternatorServerInfo(),
+			"alternator_table":               dataSourceAlternatorTable(),
 		},
 		ConfigureContextFunc: configure(),
 	}
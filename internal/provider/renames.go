@@ -0,0 +1,288 @@
+package provider
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/kota65535/alternator/cmd"
+	"github.com/kota65535/alternator/lib"
+	"github.com/kota65535/alternator/parser"
+)
+
+type schemaRename struct {
+	Table string
+	From  string
+	To    string
+}
+
+func renamesSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		Description: "Explicit renames of tables, columns and indexes, executed instead of dropping and adding them. A rename becomes no-op once it is observed in the remote database, so it can be left in the configuration.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"table": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "Table containing the renamed column or index. Omit it to rename the table itself.",
+				},
+				"from": {
+					Type:        schema.TypeString,
+					Required:    true,
+					Description: "Current name.",
+				},
+				"to": {
+					Type:        schema.TypeString,
+					Required:    true,
+					Description: "New name.",
+				},
+			},
+		},
+	}
+}
+
+func expandRenames(v interface{}) []*schemaRename {
+	ret := []*schemaRename{}
+	for _, e := range v.([]interface{}) {
+		m := e.(map[string]interface{})
+		ret = append(ret, &schemaRename{
+			Table: m["table"].(string),
+			From:  m["from"].(string),
+			To:    m["to"].(string),
+		})
+	}
+	return ret
+}
+
+// getAlterationsWithRenames works like Alternator.GetAlterations, except that the given renames are applied
+// to the remote schemas before calculating alterations. Changes to execute the renames are returned separately.
+// Partition definitions of the tables in ignorePartitions are regarded as the same as the remote ones.
+func getAlterationsWithRenames(client *cmd.Alternator, schemaStr string, renames []*schemaRename, ignorePartitions []string) (*lib.DatabaseAlterations, []*plannedChange, []*lib.Schema, []*lib.Schema, error) {
+	if len(renames) == 0 && len(ignorePartitions) == 0 {
+		alt, remoteSchemas, localSchemas, err := client.GetAlterations(schemaStr)
+		return alt, []*plannedChange{}, remoteSchemas, localSchemas, err
+	}
+	localSchemas, err := client.ReadSchemas(schemaStr)
+	if err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("failed to read local shema : %w", err)
+	}
+	remoteSchemas, err := client.FetchSchemas()
+	if err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("failed to fetch remote schema : %w", err)
+	}
+
+	changes := []*plannedChange{}
+	for _, s := range remoteSchemas {
+		c, err := applyRenames(s, renames)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		changes = append(changes, c...)
+	}
+	// Sort after renaming so that renamed tables are placed at the same position as the local ones
+	remoteSchemas = sortRemoteSchemas(remoteSchemas, localSchemas)
+	ignorePartitionDefinitions(remoteSchemas, localSchemas, ignorePartitions)
+
+	return lib.NewDatabaseAlterations(remoteSchemas, localSchemas), changes, remoteSchemas, localSchemas, nil
+}
+
+// applyRenames renames the objects in the given schema and returns the changes to do the same on the remote database.
+// Renames whose old name is not found are regarded as already executed.
+func applyRenames(s *lib.Schema, renames []*schemaRename) ([]*plannedChange, error) {
+	dbName := s.Database.DbName
+	ret := []*plannedChange{}
+
+	// Rename tables at first, so that column and index renames can refer the new table name
+	for _, r := range renames {
+		if r.Table != "" {
+			continue
+		}
+		from := findTable(s, r.From)
+		if from == nil {
+			continue
+		}
+		if findTable(s, r.To) != nil {
+			return nil, fmt.Errorf("cannot rename table %s to %s because both exist", r.From, r.To)
+		}
+		from.TableName = r.To
+		for _, t := range s.Tables {
+			for _, f := range t.GetForeignKeys() {
+				if f.ReferenceDefinition.TableName == r.From {
+					f.ReferenceDefinition.TableName = r.To
+				}
+			}
+		}
+		ret = append(ret, &plannedChange{
+			Sql:        fmt.Sprintf("RENAME TABLE `%s`.`%s` TO `%s`.`%s`;", dbName, r.From, dbName, r.To),
+			Kind:       "rename",
+			ObjectType: "table",
+			Object:     r.To,
+			Reason:     fmt.Sprintf("table %s: renamed to %s", r.From, r.To),
+		})
+	}
+
+	for _, r := range renames {
+		if r.Table == "" {
+			continue
+		}
+		t := findTable(s, r.Table)
+		if t == nil {
+			continue
+		}
+		if findColumn(t, r.From) != nil {
+			if findColumn(t, r.To) != nil {
+				return nil, fmt.Errorf("cannot rename column %s.%s to %s because both exist", r.Table, r.From, r.To)
+			}
+			renameColumn(s, t, r.From, r.To)
+			ret = append(ret, &plannedChange{
+				Sql:        fmt.Sprintf("ALTER TABLE `%s`.`%s` RENAME COLUMN `%s` TO `%s`;", dbName, t.TableName, r.From, r.To),
+				Kind:       "rename",
+				ObjectType: "column",
+				Object:     fmt.Sprintf("%s.%s", t.TableName, r.To),
+				Reason:     fmt.Sprintf("column %s: renamed to %s", r.From, r.To),
+			})
+			continue
+		}
+		if objectType := findIndexType(t, r.From); objectType != "" {
+			if findIndexType(t, r.To) != "" {
+				return nil, fmt.Errorf("cannot rename index %s.%s to %s because both exist", r.Table, r.From, r.To)
+			}
+			renameIndex(t, r.From, r.To)
+			ret = append(ret, &plannedChange{
+				Sql:        fmt.Sprintf("ALTER TABLE `%s`.`%s` RENAME INDEX `%s` TO `%s`;", dbName, t.TableName, r.From, r.To),
+				Kind:       "rename",
+				ObjectType: objectType,
+				Object:     fmt.Sprintf("%s.%s", t.TableName, r.To),
+				Reason:     fmt.Sprintf("%s %s: renamed to %s", objectType, r.From, r.To),
+			})
+		}
+	}
+
+	return ret, nil
+}
+
+func findTable(s *lib.Schema, name string) *parser.CreateTableStatement {
+	for _, t := range s.Tables {
+		if t.TableName == name {
+			return t
+		}
+	}
+	return nil
+}
+
+func findColumn(t *parser.CreateTableStatement, name string) *parser.ColumnDefinition {
+	for _, c := range t.GetColumns() {
+		if c.ColumnName == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// findIndexType returns the object type of the named index, or empty string if not found.
+func findIndexType(t *parser.CreateTableStatement, name string) string {
+	for _, i := range t.GetUniqueKeys() {
+		if i.IndexName == name {
+			return "unique key"
+		}
+	}
+	for _, i := range t.GetIndexes() {
+		if i.IndexName == name {
+			return "index"
+		}
+	}
+	for _, i := range t.GetFullTextIndexes() {
+		if i.IndexName == name {
+			return "fulltext index"
+		}
+	}
+	return ""
+}
+
+func renameColumn(s *lib.Schema, t *parser.CreateTableStatement, from string, to string) {
+	findColumn(t, from).ColumnName = to
+	for _, i := range t.GetPrimaryKeys() {
+		renameKeyPart(i.KeyPartList, from, to)
+	}
+	for _, i := range t.GetUniqueKeys() {
+		renameKeyPart(i.KeyPartList, from, to)
+	}
+	for _, i := range t.GetIndexes() {
+		renameKeyPart(i.KeyPartList, from, to)
+	}
+	for _, i := range t.GetFullTextIndexes() {
+		renameKeyPart(i.KeyPartList, from, to)
+	}
+	for _, f := range t.GetForeignKeys() {
+		renameKeyPart(f.KeyPartList, from, to)
+	}
+	// Foreign keys referencing the column follow the rename
+	for _, other := range s.Tables {
+		for _, f := range other.GetForeignKeys() {
+			if f.ReferenceDefinition.TableName == t.TableName {
+				renameKeyPart(f.ReferenceDefinition.KeyPartList, from, to)
+			}
+		}
+	}
+}
+
+func renameIndex(t *parser.CreateTableStatement, from string, to string) {
+	for _, i := range t.GetUniqueKeys() {
+		if i.IndexName == from {
+			i.IndexName = to
+		}
+	}
+	for _, i := range t.GetIndexes() {
+		if i.IndexName == from {
+			i.IndexName = to
+		}
+	}
+	for _, i := range t.GetFullTextIndexes() {
+		if i.IndexName == from {
+			i.IndexName = to
+		}
+	}
+}
+
+func renameKeyPart(keyParts []parser.KeyPart, from string, to string) {
+	for i := range keyParts {
+		if keyParts[i].Column == from {
+			keyParts[i].Column = to
+		}
+	}
+}
+
+// sortRemoteSchemas sorts remote schemas and their tables by the order of local schemas,
+// in the same way as Alternator.GetAlterations does.
+func sortRemoteSchemas(remoteSchemas []*lib.Schema, localSchemas []*lib.Schema) []*lib.Schema {
+	ret := []*lib.Schema{}
+	used := map[string]bool{}
+	for _, l := range localSchemas {
+		for _, r := range remoteSchemas {
+			if r.Database.DbName != l.Database.DbName {
+				continue
+			}
+			tables := []*parser.CreateTableStatement{}
+			usedTables := map[string]bool{}
+			for _, lt := range l.Tables {
+				if rt := findTable(r, lt.TableName); rt != nil {
+					tables = append(tables, rt)
+					usedTables[rt.TableName] = true
+				}
+			}
+			for _, rt := range r.Tables {
+				if !usedTables[rt.TableName] {
+					tables = append(tables, rt)
+				}
+			}
+			ret = append(ret, &lib.Schema{Database: r.Database, Tables: tables})
+			used[r.Database.DbName] = true
+		}
+	}
+	for _, r := range remoteSchemas {
+		if !used[r.Database.DbName] {
+			ret = append(ret, r)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,83 @@
+package provider
+
+import (
+	"github.com/kota65535/alternator/lib"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestApplyRenames(t *testing.T) {
+	remote := newTestSchemas(t, `
+CREATE DATABASE example;
+USE example;
+CREATE TABLE users
+(
+    id   int,
+    name varchar(100),
+    PRIMARY KEY (id),
+    INDEX idx_name (name)
+);
+CREATE TABLE posts
+(
+    id      int,
+    user_id int,
+    PRIMARY KEY (id),
+    FOREIGN KEY fk_user (user_id) REFERENCES users (id)
+);
+`)
+	local := newTestSchemas(t, `
+CREATE DATABASE example;
+USE example;
+CREATE TABLE members
+(
+    id           int,
+    display_name varchar(100),
+    PRIMARY KEY (id),
+    INDEX idx_display_name (display_name)
+);
+CREATE TABLE posts
+(
+    id      int,
+    user_id int,
+    PRIMARY KEY (id),
+    FOREIGN KEY fk_user (user_id) REFERENCES members (id)
+);
+`)
+	renames := []*schemaRename{
+		{From: "users", To: "members"},
+		{Table: "members", From: "name", To: "display_name"},
+		{Table: "members", From: "idx_name", To: "idx_display_name"},
+	}
+
+	changes, err := applyRenames(remote[0], renames)
+	require.NoError(t, err)
+	require.Len(t, changes, 3)
+	require.Equal(t, "RENAME TABLE `example`.`users` TO `example`.`members`;", changes[0].Sql)
+	require.Equal(t, "ALTER TABLE `example`.`members` RENAME COLUMN `name` TO `display_name`;", changes[1].Sql)
+	require.Equal(t, "ALTER TABLE `example`.`members` RENAME INDEX `idx_name` TO `idx_display_name`;", changes[2].Sql)
+	require.Equal(t, "index", changes[2].ObjectType)
+
+	// No other alterations are needed after the renames
+	alt := lib.NewDatabaseAlterations(sortRemoteSchemas(remote, local), local)
+	require.Empty(t, alt.Statements())
+
+	// Renames become no-op once they have been executed
+	changes, err = applyRenames(remote[0], renames)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+}
+
+func TestApplyRenamesConflict(t *testing.T) {
+	remote := newTestSchemas(t, `
+CREATE DATABASE example;
+USE example;
+CREATE TABLE users
+(
+    id   int,
+    name varchar(100),
+    nickname varchar(100)
+);
+`)
+	_, err := applyRenames(remote[0], []*schemaRename{{Table: "users", From: "name", To: "nickname"}})
+	require.ErrorContains(t, err, "both exist")
+}
@@ -0,0 +1,432 @@
+package provider
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"sort"
+	"strings"
+)
+
+// Maximum index key lengths of InnoDB in bytes, depending on the row format
+const (
+	maxKeyLength        = 3072
+	maxKeyLengthCompact = 767
+)
+
+// fixedKeyPartLengths are the byte lengths of non-string columns in index keys.
+var fixedKeyPartLengths = map[string]int64{
+	"tinyint":   1,
+	"smallint":  2,
+	"mediumint": 3,
+	"int":       4,
+	"bigint":    8,
+	"float":     4,
+	"double":    8,
+	"date":      3,
+	"time":      3,
+	"year":      1,
+	"datetime":  5,
+	"timestamp": 4,
+}
+
+var errKeyLengthExceeded = errors.New("indexes would exceed the maximum key length")
+
+type charsetTable struct {
+	Name      string
+	Collation string
+	RowFormat string
+	Size      int64
+	// Whether the table or any of its columns has a collation other than the target
+	NeedsConversion bool
+	Indexes         map[string][]*indexKeyPart
+}
+
+type indexKeyPart struct {
+	Column   string
+	DataType string
+	// Length in characters for string columns, either the prefix length or the column length
+	Length   int64
+	IsString bool
+}
+
+func resourceAlternatorCharsetMigration() *schema.Resource {
+	return &schema.Resource{
+		Description: "Convert all tables of a database to a character set and collation, such as from `utf8` to `utf8mb4`. " +
+			"Tables are converted by `ALTER TABLE ... CONVERT TO CHARACTER SET` in ascending order of size, and the conversion can be spread across multiple applies. " +
+			"Indexes whose key length would exceed the limit after the conversion are reported on plan, before any table is converted.",
+		CreateContext: resourceAlternatorCharsetMigrationCreate,
+		ReadContext:   resourceAlternatorCharsetMigrationRead,
+		UpdateContext: resourceAlternatorCharsetMigrationUpdate,
+		DeleteContext: resourceAlternatorCharsetMigrationDelete,
+		Schema: map[string]*schema.Schema{
+			"database": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Target database name.",
+			},
+			"character_set": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Character set to convert to.",
+			},
+			"collation": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Collation to convert to. Defaults to the default collation of `character_set`.",
+			},
+			"tables_per_apply": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				Description:  "Maximum number of tables converted in a single apply. `0` means converting all. Remaining tables are planned to be converted on the next apply.",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"pending_tables": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Tables not converted yet, in the order of conversion.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"changed": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Used by the provider internal.",
+			},
+			"statements": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Statements to execute on apply.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+		CustomizeDiff: func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+			tflog.Debug(ctx, fmt.Sprintf("@diff start"))
+
+			// cf. resourceAlternatorDatabaseSchema
+			localChanged := d.HasChange("character_set") || d.HasChange("collation") || d.HasChange("tables_per_apply")
+			remoteChanged := d.Get("changed").(bool)
+			if localChanged || remoteChanged {
+				database := d.Get("database").(string)
+				pp := meta.(*ProviderArguments)
+				if pp.Host == "" {
+					tflog.Debug(ctx, fmt.Sprintf("@diff host is empty. arguments: %+v", pp))
+					return nil
+				}
+
+				client, err := newAlternator(database, pp)
+				if err != nil {
+					tflog.Debug(ctx, fmt.Sprintf("@diff failed to initialize alternator: %s", err.Error()))
+					return nil
+				}
+				defer client.Close()
+
+				statements, _, err := planCharsetMigration(client.Db, database, d.Get("character_set").(string), d.Get("collation").(string), d.Get("tables_per_apply").(int))
+				if errors.Is(err, errDatabaseNotFound) {
+					// The database may be created in the same apply
+					tflog.Debug(ctx, fmt.Sprintf("@diff database %s not found", database))
+					err = d.SetNewComputed("pending_tables")
+					if err != nil {
+						return err
+					}
+					return d.SetNewComputed("statements")
+				}
+				if err != nil {
+					return err
+				}
+				tflog.Debug(ctx, fmt.Sprintf("@diff statements: %s", statements))
+
+				err = d.SetNewComputed("pending_tables")
+				if err != nil {
+					return err
+				}
+				// statements variable is only for showing diff on planning, and always empty value after applying it.
+				err = d.SetNew("statements", statements)
+				if err != nil {
+					return err
+				}
+			}
+
+			tflog.Debug(ctx, fmt.Sprintf("@diff end"))
+			return nil
+		},
+	}
+}
+
+func resourceAlternatorCharsetMigrationCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Debug(ctx, fmt.Sprintf("@create start"))
+
+	diags := migrateCharset(ctx, d, meta, "@create")
+	if diags.HasError() {
+		return diags
+	}
+
+	d.SetId(d.Get("database").(string))
+
+	tflog.Debug(ctx, fmt.Sprintf("@create end"))
+	return resourceAlternatorCharsetMigrationRead(ctx, d, meta)
+}
+
+func resourceAlternatorCharsetMigrationRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Debug(ctx, fmt.Sprintf("@read start"))
+
+	database := d.Get("database").(string)
+	pp := meta.(*ProviderArguments)
+	// cf. resourceAlternatorDatabaseSchemaRead
+	if pp.Host == "" {
+		tflog.Debug(ctx, fmt.Sprintf("@read host is empty. arguments: %+v", pp))
+		d.SetId("")
+		return nil
+	}
+
+	client, err := newAlternator(database, pp)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer client.Close()
+
+	// All the remaining tables are fetched regardless of tables_per_apply
+	var diags diag.Diagnostics
+	statements, pending, err := planCharsetMigration(client.Db, database, d.Get("character_set").(string), d.Get("collation").(string), 0)
+	if errors.Is(err, errDatabaseNotFound) {
+		tflog.Warn(ctx, fmt.Sprintf("@read database %s not found, removing from state", database))
+		d.SetId("")
+		return nil
+	}
+	// Violations are reported on planning, so that they do not block refreshing such as before destroying
+	if errors.Is(err, errKeyLengthExceeded) {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("Tables of database %s cannot be converted", database),
+			Detail:   err.Error(),
+		})
+	} else if err != nil {
+		return diag.FromErr(err)
+	}
+	changed := len(statements) > 0
+
+	tflog.Debug(ctx, fmt.Sprintf("@read pending_tables: %s", pending))
+	tflog.Debug(ctx, fmt.Sprintf("@read changed: %t", changed))
+
+	err = d.Set("pending_tables", pending)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	err = d.Set("changed", changed)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	err = d.Set("statements", []string{})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	tflog.Debug(ctx, fmt.Sprintf("@read end"))
+	return diags
+}
+
+func resourceAlternatorCharsetMigrationUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Debug(ctx, fmt.Sprintf("@update start"))
+
+	diags := migrateCharset(ctx, d, meta, "@update")
+	if diags.HasError() {
+		return diags
+	}
+
+	tflog.Debug(ctx, fmt.Sprintf("@update end"))
+	return resourceAlternatorCharsetMigrationRead(ctx, d, meta)
+}
+
+func resourceAlternatorCharsetMigrationDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Debug(ctx, fmt.Sprintf("@delete start"))
+
+	// Converted tables are left as they are
+	d.SetId("")
+
+	tflog.Debug(ctx, fmt.Sprintf("@delete end"))
+	return nil
+}
+
+func migrateCharset(ctx context.Context, d *schema.ResourceData, meta interface{}, label string) diag.Diagnostics {
+	database := d.Get("database").(string)
+	pp := meta.(*ProviderArguments)
+
+	client, err := newAlternator(database, pp)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer client.Close()
+
+	statements, _, err := planCharsetMigration(client.Db, database, d.Get("character_set").(string), d.Get("collation").(string), d.Get("tables_per_apply").(int))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	for _, s := range statements {
+		tflog.Info(ctx, fmt.Sprintf("%s executing statements: %s", label, s))
+		_, err := client.Db.Exec(s)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	return nil
+}
+
+// planCharsetMigration returns statements to convert at most limit tables, and names of all the tables to convert.
+// It returns an error wrapping errKeyLengthExceeded together with the statements and the tables if any index would exceed the key length limit after the conversion.
+func planCharsetMigration(db *sql.DB, database string, charset string, collation string, limit int) ([]string, []string, error) {
+	options, err := fetchDatabaseOptions(db, database)
+	if err != nil {
+		return nil, nil, err
+	}
+	if options == nil {
+		return nil, nil, fmt.Errorf("%w: %s", errDatabaseNotFound, database)
+	}
+	var defaultCollation string
+	var maxLen int64
+	err = db.QueryRow("SELECT DEFAULT_COLLATE_NAME, MAXLEN FROM information_schema.CHARACTER_SETS WHERE CHARACTER_SET_NAME = ?", charset).
+		Scan(&defaultCollation, &maxLen)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, fmt.Errorf("unknown character set %s", charset)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query character set %s : %w", charset, err)
+	}
+	if collation == "" {
+		collation = defaultCollation
+	}
+	tables, err := fetchCharsetTables(db, database, collation)
+	if err != nil {
+		return nil, nil, err
+	}
+	statements, pending, violations := charsetMigrationStatements(database, options, tables, charset, collation, maxLen, limit)
+	if len(violations) > 0 {
+		return statements, pending, fmt.Errorf("%w after converting to %s. Shorten their prefix lengths first:\n%s",
+			errKeyLengthExceeded, charset, strings.Join(violations, "\n"))
+	}
+	return statements, pending, nil
+}
+
+// charsetMigrationStatements returns statements to convert the database default and at most limit tables in ascending order of size,
+// names of all the tables to convert, and descriptions of indexes violating the key length limit.
+func charsetMigrationStatements(database string, options *databaseOptions, tables []*charsetTable, charset string, collation string, maxLen int64, limit int) ([]string, []string, []string) {
+	statements := []string{}
+	if options.CharacterSet != charset || options.Collation != collation {
+		statements = append(statements, fmt.Sprintf("ALTER DATABASE `%s` CHARACTER SET %s COLLATE %s", database, charset, collation))
+	}
+
+	pending := []*charsetTable{}
+	for _, t := range tables {
+		if t.NeedsConversion {
+			pending = append(pending, t)
+		}
+	}
+	sort.SliceStable(pending, func(i, j int) bool {
+		return pending[i].Size < pending[j].Size
+	})
+
+	names := []string{}
+	violations := []string{}
+	for i, t := range pending {
+		names = append(names, t.Name)
+		violations = append(violations, keyLengthViolations(t, maxLen)...)
+		if limit == 0 || i < limit {
+			statements = append(statements, fmt.Sprintf("ALTER TABLE `%s`.`%s` CONVERT TO CHARACTER SET %s COLLATE %s", database, t.Name, charset, collation))
+		}
+	}
+	return statements, names, violations
+}
+
+func keyLengthViolations(t *charsetTable, maxLen int64) []string {
+	limit := int64(maxKeyLength)
+	if t.RowFormat == "Compact" || t.RowFormat == "Redundant" {
+		limit = maxKeyLengthCompact
+	}
+	indexes := []string{}
+	for name := range t.Indexes {
+		indexes = append(indexes, name)
+	}
+	sort.Strings(indexes)
+
+	ret := []string{}
+	for _, name := range indexes {
+		length := indexKeyLength(t.Indexes[name], maxLen)
+		if length > limit {
+			ret = append(ret, fmt.Sprintf("%s.%s: %d bytes > %d bytes", t.Name, name, length, limit))
+		}
+	}
+	return ret
+}
+
+// indexKeyLength returns the key length of the index in bytes, when the string columns are encoded with maxLen bytes per character.
+func indexKeyLength(parts []*indexKeyPart, maxLen int64) int64 {
+	ret := int64(0)
+	for _, p := range parts {
+		if p.IsString {
+			ret += p.Length * maxLen
+		} else if l, ok := fixedKeyPartLengths[p.DataType]; ok {
+			ret += l
+		} else {
+			ret += p.Length
+		}
+	}
+	return ret
+}
+
+func fetchCharsetTables(db *sql.DB, database string, collation string) ([]*charsetTable, error) {
+	rows, err := db.Query("SELECT t.TABLE_NAME, t.TABLE_COLLATION, t.ROW_FORMAT, t.DATA_LENGTH + t.INDEX_LENGTH, "+
+		"(SELECT COUNT(*) FROM information_schema.COLUMNS c WHERE c.TABLE_SCHEMA = t.TABLE_SCHEMA AND c.TABLE_NAME = t.TABLE_NAME AND c.COLLATION_NAME <> ?) "+
+		"FROM information_schema.TABLES t WHERE t.TABLE_SCHEMA = ? AND t.TABLE_TYPE = 'BASE TABLE' ORDER BY t.TABLE_NAME", collation, database)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables : %w", err)
+	}
+	tables := []*charsetTable{}
+	tableMap := map[string]*charsetTable{}
+	for rows.Next() {
+		t := &charsetTable{Indexes: map[string][]*indexKeyPart{}}
+		var rowFormat sql.NullString
+		var size sql.NullInt64
+		var columns int
+		err := rows.Scan(&t.Name, &t.Collation, &rowFormat, &size, &columns)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to list tables : %w", err)
+		}
+		t.RowFormat = rowFormat.String
+		t.Size = size.Int64
+		t.NeedsConversion = t.Collation != collation || columns > 0
+		tables = append(tables, t)
+		tableMap[t.Name] = t
+	}
+	rows.Close()
+
+	rows, err = db.Query("SELECT s.TABLE_NAME, s.INDEX_NAME, s.COLUMN_NAME, c.DATA_TYPE, COALESCE(s.SUB_PART, c.CHARACTER_MAXIMUM_LENGTH, 0), c.CHARACTER_SET_NAME IS NOT NULL "+
+		"FROM information_schema.STATISTICS s JOIN information_schema.COLUMNS c "+
+		"ON c.TABLE_SCHEMA = s.TABLE_SCHEMA AND c.TABLE_NAME = s.TABLE_NAME AND c.COLUMN_NAME = s.COLUMN_NAME "+
+		"WHERE s.TABLE_SCHEMA = ? AND s.INDEX_TYPE <> 'FULLTEXT' ORDER BY s.TABLE_NAME, s.INDEX_NAME, s.SEQ_IN_INDEX", database)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list indexes : %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var table, index string
+		p := &indexKeyPart{}
+		err := rows.Scan(&table, &index, &p.Column, &p.DataType, &p.Length, &p.IsString)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list indexes : %w", err)
+		}
+		if t, ok := tableMap[table]; ok {
+			t.Indexes[index] = append(t.Indexes[index], p)
+		}
+	}
+	return tables, rows.Err()
+}
@@ -0,0 +1,140 @@
+package provider
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"regexp"
+	"testing"
+)
+
+func TestIndexKeyLength(t *testing.T) {
+	parts := []*indexKeyPart{
+		{Column: "id", DataType: "bigint"},
+		{Column: "name", DataType: "varchar", Length: 255, IsString: true},
+		{Column: "code", DataType: "binary", Length: 16},
+	}
+	assert.Equal(t, int64(8+255*4+16), indexKeyLength(parts, 4))
+	assert.Equal(t, int64(8+255*3+16), indexKeyLength(parts, 3))
+}
+
+func TestCharsetMigrationStatements(t *testing.T) {
+	options := &databaseOptions{CharacterSet: "utf8mb3", Collation: "utf8mb3_general_ci"}
+	tables := []*charsetTable{
+		{Name: "large", Size: 300, NeedsConversion: true, Indexes: map[string][]*indexKeyPart{}},
+		{Name: "done", Size: 100, Indexes: map[string][]*indexKeyPart{}},
+		{Name: "small", Size: 200, NeedsConversion: true, Indexes: map[string][]*indexKeyPart{}},
+	}
+
+	statements, pending, violations := charsetMigrationStatements("example", options, tables, "utf8mb4", "utf8mb4_0900_ai_ci", 4, 1)
+	assert.Equal(t, []string{
+		"ALTER DATABASE `example` CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci",
+		"ALTER TABLE `example`.`small` CONVERT TO CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci",
+	}, statements)
+	assert.Equal(t, []string{"small", "large"}, pending)
+	assert.Empty(t, violations)
+
+	options = &databaseOptions{CharacterSet: "utf8mb4", Collation: "utf8mb4_0900_ai_ci"}
+	statements, _, _ = charsetMigrationStatements("example", options, tables, "utf8mb4", "utf8mb4_0900_ai_ci", 4, 0)
+	assert.Equal(t, []string{
+		"ALTER TABLE `example`.`small` CONVERT TO CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci",
+		"ALTER TABLE `example`.`large` CONVERT TO CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci",
+	}, statements)
+}
+
+func TestKeyLengthViolations(t *testing.T) {
+	table := &charsetTable{
+		Name:      "users",
+		RowFormat: "Dynamic",
+		Indexes: map[string][]*indexKeyPart{
+			"PRIMARY": {{Column: "id", DataType: "int"}},
+			"name":    {{Column: "name", DataType: "varchar", Length: 800, IsString: true}},
+		},
+	}
+	assert.Empty(t, keyLengthViolations(table, 3))
+	assert.Equal(t, []string{"users.name: 3200 bytes > 3072 bytes"}, keyLengthViolations(table, 4))
+
+	table.RowFormat = "Compact"
+	assert.Equal(t, []string{"users.name: 2400 bytes > 767 bytes"}, keyLengthViolations(table, 3))
+}
+
+func TestAccResourceAlternatorCharsetMigration(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			// Index key too long
+			{
+				PreConfig: func() {
+					db, err := sql.Open("mysql", "root@tcp(localhost:23306)/?multiStatements=true")
+					require.NoError(t, err)
+					_, err = db.Exec("DROP DATABASE IF EXISTS example11; CREATE DATABASE example11 CHARACTER SET utf8mb3; " +
+						"CREATE TABLE example11.users (id int PRIMARY KEY, name varchar(800), KEY name (name)); " +
+						"CREATE TABLE example11.posts (id int PRIMARY KEY, title varchar(100)); " +
+						"INSERT INTO example11.posts VALUES (1, 'a'), (2, 'b')")
+					require.NoError(t, err)
+				},
+				Config:      testAccResourceAlternatorCharsetMigrationConfig(),
+				ExpectError: regexp.MustCompile("users.name: 3200 bytes > 3072 bytes"),
+			},
+			// Convert the smaller table first
+			{
+				PreConfig: func() {
+					db, err := sql.Open("mysql", "root@tcp(localhost:23306)/?multiStatements=true")
+					require.NoError(t, err)
+					_, err = db.Exec("ALTER TABLE example11.users DROP KEY name, ADD KEY name (name(191))")
+					require.NoError(t, err)
+				},
+				Config: testAccResourceAlternatorCharsetMigrationConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("alternator_charset_migration.main", "pending_tables.#", "1"),
+				),
+				ExpectNonEmptyPlan: true,
+			},
+			// Convert the rest
+			{
+				Config: testAccResourceAlternatorCharsetMigrationConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("alternator_charset_migration.main", "pending_tables.#", "0"),
+				),
+			},
+			// No changes
+			{
+				Config:   testAccResourceAlternatorCharsetMigrationConfig(),
+				PlanOnly: true,
+			},
+			// Index key too long in a table created later does not block refreshing, including the one before destroying
+			{
+				PreConfig: func() {
+					db, err := sql.Open("mysql", "root@tcp(localhost:23306)/")
+					require.NoError(t, err)
+					_, err = db.Exec("CREATE TABLE example11.tags (id int PRIMARY KEY, name varchar(800), KEY name (name)) CHARACTER SET utf8mb3")
+					require.NoError(t, err)
+				},
+				RefreshState: true,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("alternator_charset_migration.main", "pending_tables.#", "1"),
+					resource.TestCheckResourceAttr("alternator_charset_migration.main", "changed", "true"),
+				),
+			},
+			// But it is reported on planning
+			{
+				Config:      testAccResourceAlternatorCharsetMigrationConfig(),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("tags.name: 3200 bytes > 3072 bytes"),
+			},
+		},
+	})
+}
+
+func testAccResourceAlternatorCharsetMigrationConfig() string {
+	return fmt.Sprintf(`
+    %s
+	resource "alternator_charset_migration" "main" {
+        database         = "example11"
+        character_set    = "utf8mb4"
+        tables_per_apply = 1
+	}
+	`, provider)
+}
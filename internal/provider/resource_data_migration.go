@@ -0,0 +1,458 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/go-sql-driver/mysql"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"strconv"
+	"strings"
+)
+
+const errNoSuchTable = 1146
+
+const defaultTrackingTable = "alternator_data_migrations"
+
+const (
+	chunkStartPlaceholder = "{{chunk_start}}"
+	chunkEndPlaceholder   = "{{chunk_end}}"
+)
+
+func resourceAlternatorDataMigration() *schema.Resource {
+	return &schema.Resource{
+		Description: "Execute a data migration script exactly once per version. " +
+			"Executed versions are recorded in a tracking table inside the target database, and the script is never executed again once recorded, even after the resource is destroyed and created again.",
+		CreateContext: resourceAlternatorDataMigrationCreate,
+		ReadContext:   resourceAlternatorDataMigrationRead,
+		UpdateContext: resourceAlternatorDataMigrationUpdate,
+		DeleteContext: resourceAlternatorDataMigrationDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceAlternatorDataMigrationImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"database": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Target database name.",
+			},
+			"version": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Unique version of the migration. Change it to execute a new migration.",
+			},
+			"sql": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Statements of the migration. Multiple statements can be separated by `;`. They are executed in a transaction together with the record in the tracking table, so DDL statements, which cause implicit commits, should not be included. Changing it without changing `version` does not execute the migration again.",
+			},
+			"tracking_table": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     defaultTrackingTable,
+				Description: "Table recording executed migrations, created if not exists.",
+			},
+			"chunk": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				ForceNew:    true,
+				MaxItems:    1,
+				Description: "Execute the statements repeatedly for each range of the integer primary key of a large table. `{{chunk_start}}` and `{{chunk_end}}` in `sql` are replaced with the inclusive start and the exclusive end of the range, such as `WHERE id >= {{chunk_start}} AND id < {{chunk_end}}`. Each chunk is executed in its own transaction and recorded in `<tracking_table>_chunks`, so a failed migration resumes from the first unfinished chunk.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"table": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Table to split into chunks.",
+						},
+						"key": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Default:     "id",
+							Description: "Integer primary key column of the table.",
+						},
+						"size": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      1000,
+							Description:  "Range size of the key per chunk.",
+							ValidateFunc: validation.IntAtLeast(1),
+						},
+					},
+				},
+			},
+			"checksum": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "SHA-256 checksum of the executed statements, recorded in the tracking table.",
+			},
+			"executed_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Time when the migration was executed.",
+			},
+		},
+	}
+}
+
+type dataMigrationChunk struct {
+	Table string
+	Key   string
+	Size  int64
+}
+
+type dataMigrationRecord struct {
+	Checksum   string
+	ExecutedAt string
+}
+
+func resourceAlternatorDataMigrationCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Debug(ctx, fmt.Sprintf("@create start"))
+
+	database := d.Get("database").(string)
+	version := d.Get("version").(string)
+	sqlStr := d.Get("sql").(string)
+	trackingTable := d.Get("tracking_table").(string)
+	chunk := expandDataMigrationChunk(d.Get("chunk"))
+	pp := meta.(*ProviderArguments)
+
+	if chunk != nil && !strings.Contains(sqlStr, chunkStartPlaceholder) && !strings.Contains(sqlStr, chunkEndPlaceholder) {
+		return diag.Errorf("sql must contain %s or %s when chunk is specified", chunkStartPlaceholder, chunkEndPlaceholder)
+	}
+
+	client, err := newAlternator(database, pp)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer client.Close()
+
+	err = execStatements(ctx, client.Db, database, []string{createTrackingTableStatement(database, trackingTable)}, "@create")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	record, err := fetchDataMigrationRecord(client.Db, database, trackingTable, version)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if record != nil {
+		return diag.Errorf("data migration %s has already been executed at %s. Import it by \"%s\" or change the version to execute it again",
+			version, record.ExecutedAt, dataMigrationImportId(database, version, trackingTable))
+	}
+
+	// The migration is recorded in the same transaction as the statements, so that it is never executed twice
+	recordMigration := func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, fmt.Sprintf("INSERT INTO `%s`.`%s` (version, checksum) VALUES (?, ?)", database, trackingTable),
+			version, dataMigrationChecksum(sqlStr))
+		return err
+	}
+	statements := splitStatements(sqlStr)
+	if chunk == nil {
+		err = execStatementsInTransaction(ctx, client.Db, database, statements, "@create", recordMigration)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	} else {
+		chunkTable := chunkTrackingTable(trackingTable)
+		err = execStatements(ctx, client.Db, database, []string{createChunkTrackingTableStatement(database, chunkTable)}, "@create")
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		min, max, err := fetchKeyRange(client.Db, database, chunk)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		// Resume from the first unfinished chunk if the previous execution failed
+		executedEnd, err := fetchExecutedChunkEnd(client.Db, database, chunkTable, version)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if executedEnd.Valid && executedEnd.Int64 > min {
+			tflog.Info(ctx, fmt.Sprintf("@create resuming chunks from %s >= %d", chunk.Key, executedEnd.Int64))
+			min = executedEnd.Int64
+		}
+		for _, r := range chunkRanges(min, max, chunk.Size) {
+			tflog.Info(ctx, fmt.Sprintf("@create executing chunk: %s >= %d AND %s < %d", chunk.Key, r[0], chunk.Key, r[1]))
+			recordChunk := func(tx *sql.Tx) error {
+				_, err := tx.ExecContext(ctx, fmt.Sprintf("INSERT INTO `%s`.`%s` (version, chunk_start, chunk_end) VALUES (?, ?, ?)", database, chunkTable),
+					version, r[0], r[1])
+				return err
+			}
+			err = execStatementsInTransaction(ctx, client.Db, database, chunkStatements(statements, r[0], r[1]), "@create", recordChunk)
+			if err != nil {
+				return diag.Errorf("failed to execute chunk %d-%d : %s", r[0], r[1], err)
+			}
+		}
+		err = execStatementsInTransaction(ctx, client.Db, database, nil, "@create", recordMigration)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	d.SetId(dataMigrationId(database, version))
+
+	tflog.Debug(ctx, fmt.Sprintf("@create end"))
+	return resourceAlternatorDataMigrationRead(ctx, d, meta)
+}
+
+func resourceAlternatorDataMigrationRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Debug(ctx, fmt.Sprintf("@read start"))
+
+	database := d.Get("database").(string)
+	version := d.Get("version").(string)
+	trackingTable := d.Get("tracking_table").(string)
+	pp := meta.(*ProviderArguments)
+	// cf. resourceAlternatorDatabaseSchemaRead
+	if pp.Host == "" {
+		tflog.Debug(ctx, fmt.Sprintf("@read host is empty. arguments: %+v", pp))
+		d.SetId("")
+		return nil
+	}
+
+	client, err := newAlternator(database, pp)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer client.Close()
+
+	record, err := fetchDataMigrationRecord(client.Db, database, trackingTable, version)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if record == nil {
+		tflog.Warn(ctx, fmt.Sprintf("@read data migration %s is not recorded, removing from state", version))
+		d.SetId("")
+		return nil
+	}
+
+	err = d.Set("checksum", record.Checksum)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	err = d.Set("executed_at", record.ExecutedAt)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	tflog.Debug(ctx, fmt.Sprintf("@read end"))
+	return nil
+}
+
+func resourceAlternatorDataMigrationUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Debug(ctx, fmt.Sprintf("@update start"))
+
+	// The migration is never executed again for the same version
+	if d.HasChange("sql") {
+		tflog.Warn(ctx, fmt.Sprintf("@update sql is changed but data migration %s is not executed again", d.Get("version").(string)))
+	}
+
+	tflog.Debug(ctx, fmt.Sprintf("@update end"))
+	return resourceAlternatorDataMigrationRead(ctx, d, meta)
+}
+
+func resourceAlternatorDataMigrationDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Debug(ctx, fmt.Sprintf("@delete start"))
+
+	// The tracking row is kept so that the migration is not executed again
+	d.SetId("")
+
+	tflog.Debug(ctx, fmt.Sprintf("@delete end"))
+	return nil
+}
+
+func resourceAlternatorDataMigrationImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	id := d.Id()
+	tflog.Debug(ctx, fmt.Sprintf("@import id = %s", id))
+
+	database, version, trackingTable, err := parseDataMigrationImportId(id)
+	if err != nil {
+		return nil, err
+	}
+	err = d.Set("database", database)
+	if err != nil {
+		return nil, err
+	}
+	err = d.Set("version", version)
+	if err != nil {
+		return nil, err
+	}
+	err = d.Set("tracking_table", trackingTable)
+	if err != nil {
+		return nil, err
+	}
+	d.SetId(dataMigrationId(database, version))
+	return []*schema.ResourceData{d}, nil
+}
+
+func dataMigrationId(database string, version string) string {
+	return fmt.Sprintf("%s:%s", database, version)
+}
+
+// dataMigrationImportId returns the import ID, in which the tracking table is omitted if it is the default one.
+func dataMigrationImportId(database string, version string, trackingTable string) string {
+	if trackingTable == defaultTrackingTable {
+		return dataMigrationId(database, version)
+	}
+	return fmt.Sprintf("%s:%s:%s", database, version, trackingTable)
+}
+
+// parseDataMigrationImportId parses the import ID in the form of "database:version" or "database:version:tracking_table".
+func parseDataMigrationImportId(id string) (string, string, string, error) {
+	parts := strings.Split(id, ":")
+	if len(parts) == 2 {
+		parts = append(parts, defaultTrackingTable)
+	}
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return "", "", "", fmt.Errorf("import ID must be in the form of \"database:version\" or \"database:version:tracking_table\": %s", id)
+	}
+	return parts[0], parts[1], parts[2], nil
+}
+
+func dataMigrationChecksum(sqlStr string) string {
+	sum := sha256.Sum256([]byte(sqlStr))
+	return hex.EncodeToString(sum[:])
+}
+
+func createTrackingTableStatement(database string, table string) string {
+	return fmt.Sprintf("CREATE TABLE IF NOT EXISTS `%s`.`%s` (\n"+
+		"  `version` varchar(255) NOT NULL,\n"+
+		"  `checksum` char(64) NOT NULL,\n"+
+		"  `executed_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,\n"+
+		"  PRIMARY KEY (`version`)\n"+
+		")", database, table)
+}
+
+// chunkTrackingTable returns the table recording executed chunks, which is placed next to the tracking table.
+func chunkTrackingTable(trackingTable string) string {
+	return fmt.Sprintf("%s_chunks", trackingTable)
+}
+
+func createChunkTrackingTableStatement(database string, table string) string {
+	return fmt.Sprintf("CREATE TABLE IF NOT EXISTS `%s`.`%s` (\n"+
+		"  `version` varchar(255) NOT NULL,\n"+
+		"  `chunk_start` bigint NOT NULL,\n"+
+		"  `chunk_end` bigint NOT NULL,\n"+
+		"  `executed_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,\n"+
+		"  PRIMARY KEY (`version`, `chunk_start`)\n"+
+		")", database, table)
+}
+
+// fetchExecutedChunkEnd returns the exclusive end of the executed chunks of the version, or NULL if no chunks have been executed.
+func fetchExecutedChunkEnd(db *sql.DB, database string, table string, version string) (sql.NullInt64, error) {
+	var ret sql.NullInt64
+	err := db.QueryRow(fmt.Sprintf("SELECT MAX(chunk_end) FROM `%s`.`%s` WHERE version = ?", database, table), version).Scan(&ret)
+	if err != nil {
+		return ret, fmt.Errorf("failed to query %s : %w", table, err)
+	}
+	return ret, nil
+}
+
+// execStatementsInTransaction executes the statements and the record function in one transaction.
+// Note that DDL statements cannot be rolled back because they cause implicit commits.
+func execStatementsInTransaction(ctx context.Context, db *sql.DB, database string, statements []string, label string, record func(tx *sql.Tx) error) error {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	_, err = conn.ExecContext(ctx, fmt.Sprintf("USE `%s`", database))
+	if err != nil {
+		return err
+	}
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	for _, s := range statements {
+		tflog.Info(ctx, fmt.Sprintf("%s executing statements: %s", label, s))
+		_, err := tx.ExecContext(ctx, s)
+		if err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+	err = record(tx)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// fetchDataMigrationRecord returns the tracking row of the version, or nil if the version has not been executed.
+func fetchDataMigrationRecord(db *sql.DB, database string, table string, version string) (*dataMigrationRecord, error) {
+	record := &dataMigrationRecord{}
+	err := db.QueryRow(fmt.Sprintf("SELECT checksum, executed_at FROM `%s`.`%s` WHERE version = ?", database, table), version).
+		Scan(&record.Checksum, &record.ExecutedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == errNoSuchTable {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s : %w", table, err)
+	}
+	return record, nil
+}
+
+func expandDataMigrationChunk(v interface{}) *dataMigrationChunk {
+	l := v.([]interface{})
+	if len(l) == 0 || l[0] == nil {
+		return nil
+	}
+	m := l[0].(map[string]interface{})
+	return &dataMigrationChunk{
+		Table: m["table"].(string),
+		Key:   m["key"].(string),
+		Size:  int64(m["size"].(int)),
+	}
+}
+
+// fetchKeyRange returns the minimum and maximum values of the chunk key, or an empty range if the table is empty.
+func fetchKeyRange(db *sql.DB, database string, chunk *dataMigrationChunk) (int64, int64, error) {
+	var min, max sql.NullInt64
+	err := db.QueryRow(fmt.Sprintf("SELECT MIN(`%s`), MAX(`%s`) FROM `%s`.`%s`", chunk.Key, chunk.Key, database, chunk.Table)).
+		Scan(&min, &max)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to query key range of %s : %w", chunk.Table, err)
+	}
+	if !min.Valid || !max.Valid {
+		return 0, -1, nil
+	}
+	return min.Int64, max.Int64, nil
+}
+
+// chunkRanges splits [min, max] into ranges of the given size. Each range is a pair of the inclusive start and the exclusive end.
+func chunkRanges(min int64, max int64, size int64) [][2]int64 {
+	ret := [][2]int64{}
+	for start := min; start <= max; start += size {
+		end := start + size
+		if end > max {
+			end = max + 1
+		}
+		ret = append(ret, [2]int64{start, end})
+	}
+	return ret
+}
+
+func chunkStatements(statements []string, start int64, end int64) []string {
+	r := strings.NewReplacer(
+		chunkStartPlaceholder, strconv.FormatInt(start, 10),
+		chunkEndPlaceholder, strconv.FormatInt(end, 10))
+	ret := []string{}
+	for _, s := range statements {
+		ret = append(ret, r.Replace(s))
+	}
+	return ret
+}
@@ -0,0 +1,201 @@
+package provider
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/require"
+	"regexp"
+	"testing"
+)
+
+func TestAccResourceAlternatorDataMigration(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			// Create
+			{
+				PreConfig: func() {
+					db, err := sql.Open("mysql", "root@tcp(localhost:23306)/?multiStatements=true")
+					require.NoError(t, err)
+					_, err = db.Exec("DROP DATABASE IF EXISTS example7; CREATE DATABASE example7; " +
+						"CREATE TABLE example7.users (id int PRIMARY KEY, name varchar(100), display_name varchar(100)); " +
+						"INSERT INTO example7.users (id, name) VALUES (1, 'foo'), (2, 'bar'), (5, 'baz')")
+					require.NoError(t, err)
+				},
+				Config: testAccResourceAlternatorDataMigrationConfig("v1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("alternator_data_migration.main", "id", "example7:v1"),
+					resource.TestCheckResourceAttrSet("alternator_data_migration.main", "executed_at"),
+					testAccCheckDisplayNames(t, 3),
+				),
+			},
+			// Destroy keeps the tracking row
+			{
+				PreConfig: func() {
+					db, err := sql.Open("mysql", "root@tcp(localhost:23306)/")
+					require.NoError(t, err)
+					_, err = db.Exec("UPDATE example7.users SET display_name = NULL")
+					require.NoError(t, err)
+				},
+				Config: provider,
+			},
+			// Refuse to execute the recorded version again
+			{
+				Config:      testAccResourceAlternatorDataMigrationConfig("v1"),
+				ExpectError: regexp.MustCompile("has already been executed"),
+			},
+			// Import
+			{
+				Config:        testAccResourceAlternatorDataMigrationConfig("v1"),
+				ResourceName:  "alternator_data_migration.main",
+				ImportState:   true,
+				ImportStateId: "example7:v1",
+				ImportStateCheck: func(states []*terraform.InstanceState) error {
+					if len(states) != 1 || states[0].Attributes["version"] != "v1" {
+						return fmt.Errorf("unexpected import state: %+v", states)
+					}
+					return nil
+				},
+			},
+			// Import with the tracking table
+			{
+				Config:        testAccResourceAlternatorDataMigrationConfig("v1"),
+				ResourceName:  "alternator_data_migration.main",
+				ImportState:   true,
+				ImportStateId: "example7:v1:alternator_data_migrations",
+				ImportStateCheck: func(states []*terraform.InstanceState) error {
+					if len(states) != 1 || states[0].ID != "example7:v1" || states[0].Attributes["tracking_table"] != "alternator_data_migrations" {
+						return fmt.Errorf("unexpected import state: %+v", states)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func testAccCheckDisplayNames(t *testing.T, expected int) resource.TestCheckFunc {
+	return func(state *terraform.State) error {
+		db, err := sql.Open("mysql", "root@tcp(localhost:23306)/")
+		require.NoError(t, err)
+		var count int
+		err = db.QueryRow("SELECT COUNT(*) FROM example7.users WHERE display_name = name").Scan(&count)
+		require.NoError(t, err)
+		if count != expected {
+			return fmt.Errorf("expected %d rows to be migrated, but got %d", expected, count)
+		}
+		return nil
+	}
+}
+
+func testAccResourceAlternatorDataMigrationConfig(version string) string {
+	return fmt.Sprintf(`
+    %s
+	resource "alternator_data_migration" "main" {
+        database = "example7"
+        version  = "%s"
+        sql      = "UPDATE users SET display_name = name WHERE id >= {{chunk_start}} AND id < {{chunk_end}}"
+        chunk {
+            table = "users"
+            size  = 2
+        }
+	}
+	`, provider, version)
+}
+
+func TestAccResourceAlternatorDataMigrationResume(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			// Fail at the last chunk
+			{
+				PreConfig: func() {
+					db, err := sql.Open("mysql", "root@tcp(localhost:23306)/?multiStatements=true")
+					require.NoError(t, err)
+					_, err = db.Exec("DROP DATABASE IF EXISTS example14; CREATE DATABASE example14; " +
+						"CREATE TABLE example14.users (id int PRIMARY KEY, counter int NOT NULL DEFAULT 0); " +
+						"INSERT INTO example14.users (id) VALUES (1), (2), (5); " +
+						"CREATE TRIGGER example14.fail_users BEFORE UPDATE ON example14.users FOR EACH ROW " +
+						"BEGIN IF NEW.id = 5 THEN SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'failed'; END IF; END")
+					require.NoError(t, err)
+				},
+				Config:      testAccResourceAlternatorDataMigrationResumeConfig(),
+				ExpectError: regexp.MustCompile("failed to execute chunk 5-6"),
+			},
+			// Resume from the failed chunk
+			{
+				PreConfig: func() {
+					db, err := sql.Open("mysql", "root@tcp(localhost:23306)/")
+					require.NoError(t, err)
+					var count int
+					err = db.QueryRow("SELECT COUNT(*) FROM example14.alternator_data_migrations_chunks WHERE version = 'v1'").Scan(&count)
+					require.NoError(t, err)
+					require.Equal(t, 2, count)
+					_, err = db.Exec("DROP TRIGGER example14.fail_users")
+					require.NoError(t, err)
+				},
+				Config: testAccResourceAlternatorDataMigrationResumeConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("alternator_data_migration.main", "id", "example14:v1"),
+					func(state *terraform.State) error {
+						db, err := sql.Open("mysql", "root@tcp(localhost:23306)/")
+						require.NoError(t, err)
+						var count int
+						err = db.QueryRow("SELECT COUNT(*) FROM example14.users WHERE counter = 1").Scan(&count)
+						require.NoError(t, err)
+						if count != 3 {
+							return fmt.Errorf("expected each row to be migrated exactly once, but got %d rows", count)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func testAccResourceAlternatorDataMigrationResumeConfig() string {
+	return fmt.Sprintf(`
+    %s
+	resource "alternator_data_migration" "main" {
+        database = "example14"
+        version  = "v1"
+        sql      = "UPDATE users SET counter = counter + 1 WHERE id >= {{chunk_start}} AND id < {{chunk_end}}"
+        chunk {
+            table = "users"
+            size  = 2
+        }
+	}
+	`, provider)
+}
+
+func TestChunkRanges(t *testing.T) {
+	require.Equal(t, [][2]int64{{1, 3}, {3, 5}, {5, 6}}, chunkRanges(1, 5, 2))
+	require.Equal(t, [][2]int64{{1, 2}}, chunkRanges(1, 1, 1000))
+	require.Equal(t, [][2]int64{}, chunkRanges(0, -1, 1000))
+}
+
+func TestChunkStatements(t *testing.T) {
+	require.Equal(t,
+		[]string{"UPDATE users SET display_name = name WHERE id >= 10 AND id < 20"},
+		chunkStatements([]string{"UPDATE users SET display_name = name WHERE id >= {{chunk_start}} AND id < {{chunk_end}}"}, 10, 20))
+}
+
+func TestParseDataMigrationImportId(t *testing.T) {
+	database, version, trackingTable, err := parseDataMigrationImportId("example:v1")
+	require.NoError(t, err)
+	require.Equal(t, []string{"example", "v1", "alternator_data_migrations"}, []string{database, version, trackingTable})
+
+	database, version, trackingTable, err = parseDataMigrationImportId("example:v1:migrations")
+	require.NoError(t, err)
+	require.Equal(t, []string{"example", "v1", "migrations"}, []string{database, version, trackingTable})
+
+	_, _, _, err = parseDataMigrationImportId("example")
+	require.Error(t, err)
+	_, _, _, err = parseDataMigrationImportId("example::migrations")
+	require.Error(t, err)
+	_, _, _, err = parseDataMigrationImportId("example:v1:migrations:extra")
+	require.Error(t, err)
+}
@@ -0,0 +1,326 @@
+package provider
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/go-sql-driver/mysql"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"strings"
+)
+
+type databaseOptions struct {
+	CharacterSet string
+	Collation    string
+	Encryption   bool
+	ReadOnly     bool
+}
+
+func resourceAlternatorDatabase() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Manage SQL database and its options. Use it with `alternator_table` resources to manage tables separately.",
+		CreateContext: resourceAlternatorDatabaseCreate,
+		ReadContext:   resourceAlternatorDatabaseRead,
+		UpdateContext: resourceAlternatorDatabaseUpdate,
+		DeleteContext: resourceAlternatorDatabaseDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceAlternatorDatabaseImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Database name.",
+			},
+			"character_set": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Default character set of the database. The server default is used if not specified.",
+			},
+			"collation": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Default collation of the database. The default collation of the character set is used if not specified.",
+			},
+			"encryption": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Computed:    true,
+				Description: "Whether tables in the database are encrypted by default.",
+			},
+			"read_only": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether the database is read-only. Requires MySQL 8.0.22 or later.",
+			},
+			"force_destroy": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether to drop the database even if it contains tables. If false, destroying a non-empty database fails.",
+			},
+		},
+		CustomizeDiff: func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+			// Collation follows the character set unless it is specified explicitly
+			if d.HasChange("character_set") && d.GetRawConfig().GetAttr("collation").IsNull() {
+				return d.SetNewComputed("collation")
+			}
+			return nil
+		},
+	}
+}
+
+func resourceAlternatorDatabaseCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Debug(ctx, fmt.Sprintf("@create start"))
+
+	name := d.Get("name").(string)
+	pp := meta.(*ProviderArguments)
+
+	client, err := newAlternator(name, pp)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer client.Close()
+
+	options := []string{}
+	if v := d.Get("character_set").(string); v != "" {
+		options = append(options, fmt.Sprintf("CHARACTER SET %s", v))
+	}
+	if v := d.Get("collation").(string); v != "" {
+		options = append(options, fmt.Sprintf("COLLATE %s", v))
+	}
+	if !d.GetRawConfig().GetAttr("encryption").IsNull() {
+		options = append(options, fmt.Sprintf("ENCRYPTION '%s'", encryptionValue(d.Get("encryption").(bool))))
+	}
+	statements := []string{
+		strings.TrimSpace(fmt.Sprintf("CREATE DATABASE `%s` %s", name, strings.Join(options, " "))),
+	}
+	if d.Get("read_only").(bool) {
+		statements = append(statements, fmt.Sprintf("ALTER DATABASE `%s` READ ONLY = 1", name))
+	}
+	for _, s := range statements {
+		tflog.Info(ctx, fmt.Sprintf("@create executing statements: %s", s))
+		_, err := client.Db.Exec(s)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	d.SetId(name)
+
+	tflog.Debug(ctx, fmt.Sprintf("@create end"))
+	return resourceAlternatorDatabaseRead(ctx, d, meta)
+}
+
+func resourceAlternatorDatabaseRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Debug(ctx, fmt.Sprintf("@read start"))
+
+	name := d.Id()
+	pp := meta.(*ProviderArguments)
+	// cf. resourceAlternatorDatabaseSchemaRead
+	if pp.Host == "" {
+		tflog.Debug(ctx, fmt.Sprintf("@read host is empty. arguments: %+v", pp))
+		d.SetId("")
+		return nil
+	}
+
+	client, err := newAlternator(name, pp)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer client.Close()
+
+	options, err := fetchDatabaseOptions(client.Db, name)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if options == nil {
+		tflog.Warn(ctx, fmt.Sprintf("@read database %s not found, removing from state", name))
+		d.SetId("")
+		return nil
+	}
+	tflog.Debug(ctx, fmt.Sprintf("@read options: %+v", options))
+
+	err = d.Set("name", name)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	err = d.Set("character_set", options.CharacterSet)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	err = d.Set("collation", options.Collation)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	err = d.Set("encryption", options.Encryption)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	err = d.Set("read_only", options.ReadOnly)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	tflog.Debug(ctx, fmt.Sprintf("@read end"))
+	return nil
+}
+
+func resourceAlternatorDatabaseUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Debug(ctx, fmt.Sprintf("@update start"))
+
+	name := d.Get("name").(string)
+	pp := meta.(*ProviderArguments)
+
+	client, err := newAlternator(name, pp)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer client.Close()
+
+	options := []string{}
+	if d.HasChange("character_set") {
+		options = append(options, fmt.Sprintf("CHARACTER SET %s", d.Get("character_set").(string)))
+	}
+	// Unspecified collation has already been marked as computed on planning
+	if d.HasChange("collation") && !d.GetRawConfig().GetAttr("collation").IsNull() {
+		options = append(options, fmt.Sprintf("COLLATE %s", d.Get("collation").(string)))
+	}
+	if d.HasChange("encryption") {
+		options = append(options, fmt.Sprintf("ENCRYPTION '%s'", encryptionValue(d.Get("encryption").(bool))))
+	}
+
+	// Read-only database must be made writable before altering other options
+	statements := []string{}
+	readOnly := d.Get("read_only").(bool)
+	if d.HasChange("read_only") && !readOnly {
+		statements = append(statements, fmt.Sprintf("ALTER DATABASE `%s` READ ONLY = 0", name))
+	}
+	if len(options) > 0 {
+		statements = append(statements, fmt.Sprintf("ALTER DATABASE `%s` %s", name, strings.Join(options, " ")))
+	}
+	if d.HasChange("read_only") && readOnly {
+		statements = append(statements, fmt.Sprintf("ALTER DATABASE `%s` READ ONLY = 1", name))
+	}
+	for _, s := range statements {
+		tflog.Info(ctx, fmt.Sprintf("@update executing statements: %s", s))
+		_, err := client.Db.Exec(s)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	tflog.Debug(ctx, fmt.Sprintf("@update end"))
+	return resourceAlternatorDatabaseRead(ctx, d, meta)
+}
+
+func resourceAlternatorDatabaseDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Debug(ctx, fmt.Sprintf("@delete start"))
+
+	name := d.Get("name").(string)
+	pp := meta.(*ProviderArguments)
+
+	client, err := newAlternator(name, pp)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer client.Close()
+
+	var nTables int
+	err = client.Db.QueryRow("SELECT COUNT(*) FROM information_schema.TABLES WHERE TABLE_SCHEMA = ?", name).Scan(&nTables)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if nTables > 0 && !d.Get("force_destroy").(bool) {
+		return diag.Errorf("database %s contains %d tables. set force_destroy to true to drop it", name, nTables)
+	}
+
+	statements := []string{}
+	if d.Get("read_only").(bool) {
+		statements = append(statements, fmt.Sprintf("ALTER DATABASE `%s` READ ONLY = 0", name))
+	}
+	statements = append(statements, fmt.Sprintf("DROP DATABASE IF EXISTS `%s`", name))
+	for _, s := range statements {
+		tflog.Info(ctx, fmt.Sprintf("@delete executing statements: %s", s))
+		_, err := client.Db.Exec(s)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	d.SetId("")
+
+	tflog.Debug(ctx, fmt.Sprintf("@delete end"))
+	return nil
+}
+
+func resourceAlternatorDatabaseImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	name := d.Id()
+	tflog.Debug(ctx, fmt.Sprintf("@import id = %s", name))
+	err := d.Set("name", name)
+	if err != nil {
+		return nil, err
+	}
+	err = d.Set("force_destroy", false)
+	if err != nil {
+		return nil, err
+	}
+	d.SetId(name)
+	return []*schema.ResourceData{d}, nil
+}
+
+// MySQL error numbers of unavailable information_schema columns and tables
+const (
+	errUnknownColumn = 1054
+	errUnknownTable  = 1109
+)
+
+// fetchDatabaseOptions returns options of the database, or nil if the database does not exist.
+func fetchDatabaseOptions(db *sql.DB, name string) (*databaseOptions, error) {
+	var charset, collation, encryption string
+	err := db.QueryRow("SELECT DEFAULT_CHARACTER_SET_NAME, DEFAULT_COLLATION_NAME, DEFAULT_ENCRYPTION FROM information_schema.SCHEMATA WHERE SCHEMA_NAME = ?", name).
+		Scan(&charset, &collation, &encryption)
+	// DEFAULT_ENCRYPTION is available since MySQL 8.0.16, and not available on MariaDB
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == errUnknownColumn {
+		err = db.QueryRow("SELECT DEFAULT_CHARACTER_SET_NAME, DEFAULT_COLLATION_NAME FROM information_schema.SCHEMATA WHERE SCHEMA_NAME = ?", name).
+			Scan(&charset, &collation)
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query database options : %w", err)
+	}
+
+	// SCHEMATA_EXTENSIONS is available since MySQL 8.0.22
+	var extensions sql.NullString
+	err = db.QueryRow("SELECT OPTIONS FROM information_schema.SCHEMATA_EXTENSIONS WHERE SCHEMA_NAME = ?", name).Scan(&extensions)
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == errUnknownTable {
+		err = nil
+	}
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to query database extensions : %w", err)
+	}
+
+	return &databaseOptions{
+		CharacterSet: charset,
+		Collation:    collation,
+		Encryption:   encryption == "YES",
+		ReadOnly:     strings.Contains(extensions.String, "READ ONLY=1"),
+	}, nil
+}
+
+func encryptionValue(enabled bool) string {
+	if enabled {
+		return "Y"
+	}
+	return "N"
+}
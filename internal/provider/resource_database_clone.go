@@ -0,0 +1,538 @@
+package provider
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/kota65535/alternator/lib"
+	"strings"
+)
+
+// maxPlaceholders is the maximum number of placeholders in a single prepared statement of MySQL.
+const maxPlaceholders = 65535
+
+type columnMasking struct {
+	Table  string
+	Column string
+	Method string
+	Value  string
+	// Whether value is specified, since an empty string is a valid value
+	HasValue bool
+}
+
+type cloneColumn struct {
+	Name      string
+	DataType  string
+	MaxLength sql.NullInt64
+}
+
+// hashIntegerMaxValues is the maximum values of integer types that hash method can mask.
+// CRC32 checksums are reduced not to exceed them, and 0 means no reduction needed.
+var hashIntegerMaxValues = map[string]int64{
+	"tinyint":   127,
+	"smallint":  32767,
+	"mediumint": 8388607,
+	"int":       2147483647,
+	"bigint":    0,
+}
+
+// hashStringTypes is string types that hash method can mask.
+var hashStringTypes = []string{"char", "varchar", "tinytext", "text", "mediumtext", "longtext"}
+
+func resourceAlternatorDatabaseClone() *schema.Resource {
+	return &schema.Resource{
+		Description: "Create a database by copying the tables of a source database, such as a preview database per pull request copied from staging. " +
+			"The source database can be on another host. Views, triggers, stored procedures, functions and events are not copied. " +
+			"The clone is dropped on destroy.",
+		CreateContext: resourceAlternatorDatabaseCloneCreate,
+		ReadContext:   resourceAlternatorDatabaseCloneRead,
+		DeleteContext: resourceAlternatorDatabaseCloneDelete,
+		Schema: map[string]*schema.Schema{
+			"source_database": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Database name to copy from.",
+			},
+			"source_host": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Host of the source database, such as `localhost:3306`. Defaults to the provider host.",
+			},
+			"source_user": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "User of the source database. Defaults to the provider user.",
+			},
+			"source_password": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Sensitive:   true,
+				Description: "Password of the source database. Defaults to the provider password.",
+			},
+			"database": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Database name to create. It must not exist.",
+			},
+			"copy_data": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     false,
+				Description: "Whether to copy the rows of the tables in addition to the structure.",
+			},
+			"row_limit": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      0,
+				Description:  "Maximum number of rows copied per table. `0` means unlimited. Foreign key checks are disabled while copying, so the rows can reference missing ones.",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"masking": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Rules to mask columns of the copied rows.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"table": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Table name.",
+						},
+						"column": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Column name.",
+						},
+						"method": {
+							Type:     schema.TypeString,
+							Required: true,
+							Description: "How to mask the values. `hash` replaces them with SHA-256 hashes truncated to the column length for string columns, " +
+								"or CRC32 checksums for integer columns, and cannot be used for the other types. " +
+								"Note that the hashes can collide, so copying rows may fail with duplicate entry errors if the column has a unique key, especially if the column is short. " +
+								"`null` replaces them with NULL, and `fixed` with `value`.",
+							ValidateFunc: validation.StringInSlice([]string{"hash", "null", "fixed"}, false),
+						},
+						"value": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Value used by `fixed` method, which can be an empty string.",
+						},
+					},
+				},
+			},
+			"tables": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Names of the copied tables.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"copied_rows": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Description: "Number of rows copied to each table.",
+				Elem: &schema.Schema{
+					Type: schema.TypeInt,
+				},
+			},
+		},
+	}
+}
+
+func resourceAlternatorDatabaseCloneCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Debug(ctx, fmt.Sprintf("@create start"))
+
+	sourceDatabase := d.Get("source_database").(string)
+	database := d.Get("database").(string)
+	copyData := d.Get("copy_data").(bool)
+	rowLimit := d.Get("row_limit").(int)
+	maskings := expandColumnMaskings(d.Get("masking"), d.GetRawConfig().GetAttr("masking"))
+	pp := meta.(*ProviderArguments)
+	sp := sourceProviderArguments(d, pp)
+
+	source, err := newAlternator(sourceDatabase, sp)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer source.Close()
+	client, err := newAlternator(database, pp)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer client.Close()
+
+	sourceSchemas, err := source.FetchSchemas()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if len(sourceSchemas) == 0 {
+		return diag.Errorf("source database %s does not exist", sourceDatabase)
+	}
+	options, err := fetchDatabaseOptions(client.Db, database)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if options != nil {
+		return diag.Errorf("database %s already exists", database)
+	}
+	sourceSchema := sourceSchemas[0]
+	tables := []string{}
+	for _, t := range sourceSchema.Tables {
+		tables = append(tables, t.TableName)
+	}
+	columns := map[string][]*cloneColumn{}
+	for _, t := range tables {
+		columns[t], err = fetchCloneColumns(source.Db, sourceDatabase, t)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	err = validateColumnMaskings(maskings, columns)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	conn, err := client.Db.Conn(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer conn.Close()
+	// Tables are created in any order, and copied rows may reference ones not copied due to the row limit
+	_, err = conn.ExecContext(ctx, "SET FOREIGN_KEY_CHECKS = 0")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(database)
+	for _, s := range cloneSchemaStatements(sourceSchema, database) {
+		tflog.Info(ctx, fmt.Sprintf("@create executing statements: %s", s))
+		_, err := conn.ExecContext(ctx, s)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	copiedRows := map[string]int{}
+	if copyData {
+		for _, t := range tables {
+			n, err := copyTableRows(ctx, source.Db, conn, sourceDatabase, database, t, columns[t], rowLimit, maskings)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+			tflog.Info(ctx, fmt.Sprintf("@create copied %d rows to %s.%s", n, database, t))
+			copiedRows[t] = n
+		}
+	}
+
+	err = d.Set("copied_rows", copiedRows)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	tflog.Debug(ctx, fmt.Sprintf("@create end"))
+	return resourceAlternatorDatabaseCloneRead(ctx, d, meta)
+}
+
+func resourceAlternatorDatabaseCloneRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Debug(ctx, fmt.Sprintf("@read start"))
+
+	database := d.Id()
+	pp := meta.(*ProviderArguments)
+	// cf. resourceAlternatorDatabaseSchemaRead
+	if pp.Host == "" {
+		tflog.Debug(ctx, fmt.Sprintf("@read host is empty. arguments: %+v", pp))
+		d.SetId("")
+		return nil
+	}
+
+	client, err := newAlternator(database, pp)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer client.Close()
+
+	schemas, err := client.FetchSchemas()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if len(schemas) == 0 {
+		tflog.Warn(ctx, fmt.Sprintf("@read database %s not found, removing from state", database))
+		d.SetId("")
+		return nil
+	}
+	tables := []string{}
+	for _, t := range schemas[0].Tables {
+		tables = append(tables, t.TableName)
+	}
+
+	err = d.Set("tables", tables)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	tflog.Debug(ctx, fmt.Sprintf("@read end"))
+	return nil
+}
+
+func resourceAlternatorDatabaseCloneDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Debug(ctx, fmt.Sprintf("@delete start"))
+
+	database := d.Id()
+	pp := meta.(*ProviderArguments)
+
+	client, err := newAlternator("", pp)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer client.Close()
+
+	s := fmt.Sprintf("DROP DATABASE IF EXISTS `%s`", database)
+	tflog.Info(ctx, fmt.Sprintf("@delete executing statements: %s", s))
+	_, err = client.Db.Exec(s)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+
+	tflog.Debug(ctx, fmt.Sprintf("@delete end"))
+	return nil
+}
+
+// sourceProviderArguments returns the connection settings of the source database, falling back to the provider ones.
+func sourceProviderArguments(d *schema.ResourceData, pp *ProviderArguments) *ProviderArguments {
+	ret := *pp
+	if v := d.Get("source_host").(string); v != "" {
+		ret.Host = v
+	}
+	if v := d.Get("source_user").(string); v != "" {
+		ret.User = v
+	}
+	if v := d.Get("source_password").(string); v != "" {
+		ret.Password = v
+	}
+	return &ret
+}
+
+// cloneSchemaStatements returns statements to create the schema as the database of the given name.
+func cloneSchemaStatements(s *lib.Schema, database string) []string {
+	db := *s.Database
+	db.DbName = database
+	statements := []string{db.String()}
+	for _, t := range s.Tables {
+		table := *t
+		table.DbName = database
+		statements = append(statements, table.String())
+	}
+	return splitStatements(strings.Join(statements, "\n"))
+}
+
+func fetchCloneColumns(db *sql.DB, database string, table string) ([]*cloneColumn, error) {
+	// Generated columns cannot be inserted
+	rows, err := db.Query("SELECT COLUMN_NAME, DATA_TYPE, CHARACTER_MAXIMUM_LENGTH FROM information_schema.COLUMNS "+
+		"WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND EXTRA NOT LIKE '%GENERATED%' ORDER BY ORDINAL_POSITION", database, table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query columns of %s : %w", table, err)
+	}
+	defer rows.Close()
+	ret := []*cloneColumn{}
+	for rows.Next() {
+		c := &cloneColumn{}
+		err := rows.Scan(&c.Name, &c.DataType, &c.MaxLength)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query columns of %s : %w", table, err)
+		}
+		ret = append(ret, c)
+	}
+	return ret, rows.Err()
+}
+
+// copyTableRows copies the rows of the source table into the target table by batched inserts, and returns the number of the copied rows.
+func copyTableRows(ctx context.Context, source *sql.DB, target *sql.Conn, sourceDatabase string, database string, table string,
+	columns []*cloneColumn, rowLimit int, maskings []*columnMasking) (int, error) {
+	if len(columns) == 0 {
+		return 0, nil
+	}
+	query := copySelectQuery(sourceDatabase, table, columns, rowLimit, maskings)
+	tflog.Info(ctx, fmt.Sprintf("@create executing query: %s", query))
+	rows, err := source.QueryContext(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query %s : %w", table, err)
+	}
+	defer rows.Close()
+
+	names := []string{}
+	for _, c := range columns {
+		names = append(names, c.Name)
+	}
+	batchSize := maxPlaceholders / len(columns)
+	if batchSize > 1000 {
+		batchSize = 1000
+	}
+
+	count := 0
+	args := []interface{}{}
+	flush := func() error {
+		if len(args) == 0 {
+			return nil
+		}
+		n := len(args) / len(columns)
+		placeholders := fmt.Sprintf("(%s)", strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", "))
+		s := fmt.Sprintf("INSERT INTO `%s`.`%s` (%s) VALUES %s", database, table, quoteColumns(names),
+			strings.TrimSuffix(strings.Repeat(placeholders+", ", n), ", "))
+		_, err := target.ExecContext(ctx, s, args...)
+		if err != nil {
+			return fmt.Errorf("failed to insert into %s : %w", table, err)
+		}
+		count += n
+		args = []interface{}{}
+		return nil
+	}
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		dest := []interface{}{}
+		for i := range values {
+			dest = append(dest, &values[i])
+		}
+		err := rows.Scan(dest...)
+		if err != nil {
+			return 0, fmt.Errorf("failed to scan %s : %w", table, err)
+		}
+		args = append(args, values...)
+		if len(args)/len(columns) == batchSize {
+			err = flush()
+			if err != nil {
+				return 0, err
+			}
+		}
+	}
+	err = rows.Err()
+	if err != nil {
+		return 0, err
+	}
+	err = flush()
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func copySelectQuery(database string, table string, columns []*cloneColumn, rowLimit int, maskings []*columnMasking) string {
+	exprs := []string{}
+	for _, c := range columns {
+		exprs = append(exprs, maskedColumnExpression(c, findColumnMasking(maskings, table, c.Name)))
+	}
+	query := fmt.Sprintf("SELECT %s FROM `%s`.`%s`", strings.Join(exprs, ", "), database, table)
+	if rowLimit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", rowLimit)
+	}
+	return query
+}
+
+func maskedColumnExpression(c *cloneColumn, m *columnMasking) string {
+	name := fmt.Sprintf("`%s`", c.Name)
+	if m == nil {
+		return name
+	}
+	switch m.Method {
+	case "null":
+		return "NULL"
+	case "fixed":
+		return quoteString(m.Value)
+	case "hash":
+		// Column types have been validated by validateColumnMaskings
+		if maxValue, ok := hashIntegerMaxValues[c.DataType]; ok {
+			if maxValue == 0 {
+				return fmt.Sprintf("CRC32(%s)", name)
+			}
+			return fmt.Sprintf("MOD(CRC32(%s), %d)", name, maxValue+1)
+		}
+		return fmt.Sprintf("LEFT(SHA2(%s, 256), %d)", name, c.MaxLength.Int64)
+	}
+	return name
+}
+
+func findCloneColumn(columns []*cloneColumn, name string) *cloneColumn {
+	for _, c := range columns {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+func findColumnMasking(maskings []*columnMasking, table string, column string) *columnMasking {
+	for _, m := range maskings {
+		if m.Table == table && m.Column == column {
+			return m
+		}
+	}
+	return nil
+}
+
+// validateColumnMaskings validates the maskings against the columns of the source tables, keyed by the table names.
+func validateColumnMaskings(maskings []*columnMasking, columns map[string][]*cloneColumn) error {
+	for i, m := range maskings {
+		tableColumns, ok := columns[m.Table]
+		if !ok {
+			return fmt.Errorf("masking.%d: table %s does not exist in the source database", i, m.Table)
+		}
+		c := findCloneColumn(tableColumns, m.Column)
+		if c == nil {
+			return fmt.Errorf("masking.%d: column %s.%s does not exist in the source database", i, m.Table, m.Column)
+		}
+		if m.Method == "fixed" && !m.HasValue {
+			return fmt.Errorf("masking.%d: value is required for fixed method", i)
+		}
+		if m.Method == "hash" && !hashable(c) {
+			return fmt.Errorf("masking.%d: hash method cannot be used for column %s.%s of type %s", i, m.Table, m.Column, c.DataType)
+		}
+	}
+	return nil
+}
+
+func hashable(c *cloneColumn) bool {
+	if _, ok := hashIntegerMaxValues[c.DataType]; ok {
+		return true
+	}
+	for _, t := range hashStringTypes {
+		if t == c.DataType && c.MaxLength.Valid {
+			return true
+		}
+	}
+	return false
+}
+
+// expandColumnMaskings expands the masking blocks. The raw configuration is used to tell an empty value from an absent one.
+func expandColumnMaskings(v interface{}, raw cty.Value) []*columnMasking {
+	rawMaskings := []cty.Value{}
+	if !raw.IsNull() && raw.IsKnown() {
+		rawMaskings = raw.AsValueSlice()
+	}
+	ret := []*columnMasking{}
+	for i, e := range v.([]interface{}) {
+		m := e.(map[string]interface{})
+		ret = append(ret, &columnMasking{
+			Table:    m["table"].(string),
+			Column:   m["column"].(string),
+			Method:   m["method"].(string),
+			Value:    m["value"].(string),
+			HasValue: i < len(rawMaskings) && !rawMaskings[i].GetAttr("value").IsNull(),
+		})
+	}
+	return ret
+}
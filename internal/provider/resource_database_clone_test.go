@@ -0,0 +1,147 @@
+package provider
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestAccResourceAlternatorDatabaseClone(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: providerFactories,
+		CheckDestroy: func(state *terraform.State) error {
+			db, err := sql.Open("mysql", "root@tcp(localhost:23306)/")
+			require.NoError(t, err)
+			var count int
+			err = db.QueryRow("SELECT COUNT(*) FROM information_schema.SCHEMATA WHERE SCHEMA_NAME = 'example9_clone'").Scan(&count)
+			require.NoError(t, err)
+			if count != 0 {
+				return fmt.Errorf("database example9_clone still exists")
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			// Create
+			{
+				PreConfig: func() {
+					db, err := sql.Open("mysql", "root@tcp(localhost:23306)/?multiStatements=true")
+					require.NoError(t, err)
+					_, err = db.Exec("DROP DATABASE IF EXISTS example9; DROP DATABASE IF EXISTS example9_clone; CREATE DATABASE example9; " +
+						"CREATE TABLE example9.users (id int PRIMARY KEY, email varchar(100), name varchar(100)); " +
+						"CREATE TABLE example9.posts (id int PRIMARY KEY, user_id int, FOREIGN KEY (user_id) REFERENCES example9.users (id)); " +
+						"INSERT INTO example9.users VALUES (1, 'foo@example.com', 'foo'), (2, 'bar@example.com', 'bar'), (3, 'baz@example.com', 'baz'); " +
+						"INSERT INTO example9.posts VALUES (1, 1), (2, 3)")
+					require.NoError(t, err)
+				},
+				Config: testAccResourceAlternatorDatabaseCloneConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("alternator_database_clone.main", "tables.#", "2"),
+					resource.TestCheckResourceAttr("alternator_database_clone.main", "copied_rows.users", "2"),
+					resource.TestCheckResourceAttr("alternator_database_clone.main", "copied_rows.posts", "2"),
+					func(state *terraform.State) error {
+						db, err := sql.Open("mysql", "root@tcp(localhost:23306)/")
+						require.NoError(t, err)
+						var email sql.NullString
+						var name string
+						err = db.QueryRow("SELECT email, name FROM example9_clone.users WHERE id = 1").Scan(&email, &name)
+						require.NoError(t, err)
+						if email.Valid || name != "masked" {
+							return fmt.Errorf("columns are not masked: %v, %s", email, name)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func testAccResourceAlternatorDatabaseCloneConfig() string {
+	return fmt.Sprintf(`
+    %s
+	resource "alternator_database_clone" "main" {
+        source_database = "example9"
+        database        = "example9_clone"
+        copy_data       = true
+        row_limit       = 2
+        masking {
+            table  = "users"
+            column = "email"
+            method = "null"
+        }
+        masking {
+            table  = "users"
+            column = "name"
+            method = "fixed"
+            value  = "masked"
+        }
+	}
+	`, provider)
+}
+
+func TestCopySelectQuery(t *testing.T) {
+	columns := []*cloneColumn{
+		{Name: "id", DataType: "bigint"},
+		{Name: "email", DataType: "varchar", MaxLength: sql.NullInt64{Int64: 100, Valid: true}},
+		{Name: "phone", DataType: "varchar", MaxLength: sql.NullInt64{Int64: 20, Valid: true}},
+		{Name: "age", DataType: "tinyint"},
+		{Name: "name", DataType: "varchar", MaxLength: sql.NullInt64{Int64: 100, Valid: true}},
+		{Name: "code", DataType: "bigint"},
+	}
+	maskings := []*columnMasking{
+		{Table: "users", Column: "email", Method: "hash"},
+		{Table: "users", Column: "phone", Method: "null"},
+		{Table: "users", Column: "age", Method: "hash"},
+		{Table: "users", Column: "name", Method: "fixed", Value: "John's"},
+		{Table: "users", Column: "code", Method: "hash"},
+		{Table: "posts", Column: "id", Method: "null"},
+	}
+	require.Equal(t,
+		"SELECT `id`, LEFT(SHA2(`email`, 256), 100), NULL, MOD(CRC32(`age`), 128), 'John''s', CRC32(`code`) FROM `example`.`users` LIMIT 10",
+		copySelectQuery("example", "users", columns, 10, maskings))
+	require.Equal(t,
+		"SELECT `id` FROM `example`.`users`",
+		copySelectQuery("example", "users", columns[:1], 0, nil))
+}
+
+func TestValidateColumnMaskings(t *testing.T) {
+	columns := map[string][]*cloneColumn{
+		"users": {
+			{Name: "id", DataType: "int"},
+			{Name: "email", DataType: "varchar", MaxLength: sql.NullInt64{Int64: 100, Valid: true}},
+			{Name: "created_at", DataType: "datetime"},
+			{Name: "settings", DataType: "json"},
+		},
+	}
+	require.NoError(t, validateColumnMaskings([]*columnMasking{{Table: "users", Column: "email", Method: "null"}}, columns))
+	require.NoError(t, validateColumnMaskings([]*columnMasking{{Table: "users", Column: "id", Method: "hash"}}, columns))
+	require.NoError(t, validateColumnMaskings([]*columnMasking{{Table: "users", Column: "email", Method: "hash"}}, columns))
+	require.Error(t, validateColumnMaskings([]*columnMasking{{Table: "posts", Column: "email", Method: "null"}}, columns))
+	require.Error(t, validateColumnMaskings([]*columnMasking{{Table: "users", Column: "phone", Method: "null"}}, columns))
+	require.Error(t, validateColumnMaskings([]*columnMasking{{Table: "users", Column: "email", Method: "fixed"}}, columns))
+	require.NoError(t, validateColumnMaskings([]*columnMasking{{Table: "users", Column: "email", Method: "fixed", Value: "", HasValue: true}}, columns))
+	require.Error(t, validateColumnMaskings([]*columnMasking{{Table: "users", Column: "created_at", Method: "hash"}}, columns))
+	require.Error(t, validateColumnMaskings([]*columnMasking{{Table: "users", Column: "settings", Method: "hash"}}, columns))
+}
+
+func TestExpandColumnMaskings(t *testing.T) {
+	v := []interface{}{
+		map[string]interface{}{"table": "users", "column": "email", "method": "fixed", "value": ""},
+		map[string]interface{}{"table": "users", "column": "phone", "method": "fixed", "value": ""},
+	}
+	raw := cty.ListVal([]cty.Value{
+		cty.ObjectVal(map[string]cty.Value{
+			"table": cty.StringVal("users"), "column": cty.StringVal("email"), "method": cty.StringVal("fixed"), "value": cty.StringVal(""),
+		}),
+		cty.ObjectVal(map[string]cty.Value{
+			"table": cty.StringVal("users"), "column": cty.StringVal("phone"), "method": cty.StringVal("fixed"), "value": cty.NullVal(cty.String),
+		}),
+	})
+	maskings := expandColumnMaskings(v, raw)
+	require.True(t, maskings[0].HasValue)
+	require.False(t, maskings[1].HasValue)
+}
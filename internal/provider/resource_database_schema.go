@@ -6,6 +6,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/kota65535/alternator/cmd"
 	"strings"
 )
@@ -24,19 +25,51 @@ func resourceAlternatorDatabaseSchema() *schema.Resource {
 			"database": {
 				Type:        schema.TypeString,
 				Required:    true,
-				ForceNew:    true,
-				Description: "Target database name.",
+				Description: "Target database name. Changing it renames the database by moving all the tables into a new database, and other changes of `schema` in the same apply are shown after the rename.",
 			},
 			"schema": {
 				Type:        schema.TypeString,
 				Required:    true,
-				Description: "SQL Database schema definition, composed by DDL statements.",
+				Description: "SQL Database schema definition, composed by DDL statements. Views, triggers, stored procedures, functions and events can also be included, using `DELIMITER` command for ones containing compound statements. Their definitions are compared with the previous value of this argument, so only their creation and deletion are detected as changes made outside of Terraform.",
+			},
+			"drift_policy": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "revert",
+				Description:  "How to handle changes made to the remote database outside of Terraform. `revert` plans statements to revert them, `warn` only reports them until `schema` is changed, and `error` fails the plan if it would revert them.",
+				ValidateFunc: validation.StringInSlice([]string{"revert", "warn", "error"}, false),
+			},
+			"renames": renamesSchema(),
+			"ignore_partitions": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Tables whose partition definitions are ignored, such as ones rotated by `alternator_partition_rotation`. The partitioning method is still compared.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"initial_data": initialDataSchema(),
+			"initial_data_rows": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Description: "Number of rows affected by each file of `initial_data` on creation.",
+				Elem: &schema.Schema{
+					Type: schema.TypeInt,
+				},
 			},
 			"remote_schema": {
 				Type:        schema.TypeString,
 				Computed:    true,
 				Description: "Actual remote database schema definition.",
 			},
+			"drift_statements": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Statements to revert the changes made to the remote database outside of Terraform.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
 			"changed": {
 				Type:        schema.TypeBool,
 				Computed:    true,
@@ -50,17 +83,91 @@ func resourceAlternatorDatabaseSchema() *schema.Resource {
 					Type: schema.TypeString,
 				},
 			},
+			"planned_changes": plannedChangesSchema(),
+			"change_summary": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Summary of changes to apply, such as `+1 table, ~2 columns, -1 index`, or `no changes`.",
+			},
+			"tables": tablesSchema(),
 		},
 		CustomizeDiff: func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
 			tflog.Debug(ctx, fmt.Sprintf("@diff start"))
 
+			if d.Id() == "" && d.NewValueKnown("initial_data") {
+				err := validateInitialData(expandInitialData(d.Get("initial_data")))
+				if err != nil {
+					return err
+				}
+			}
+
 			// We can easily detect change of the input variables in this way
-			localSchemaChanged := d.HasChange("schema")
+			localSchemaChanged := d.HasChange("schema") || d.HasChange("renames") || d.HasChange("ignore_partitions")
 			// As for the computed variables, we cannot simply compare their old & new value,
 			// because their old value has already been updated to match the result of our read function.
 			// So we have to use the dedicated boolean computed variable.
 			// cf. https://discuss.hashicorp.com/t/force-new-resource-based-on-api-read-difference/29759/3
 			remoteSchemaChanged := d.Get("changed").(bool)
+			// Drift is not reverted if the policy is "warn"
+			if d.Get("drift_policy").(string) == "warn" {
+				remoteSchemaChanged = false
+			}
+			// Rename the database instead of recreating it
+			if d.Id() != "" && d.HasChange("database") {
+				oldDatabase, database := d.GetChange("database")
+				pp := meta.(*ProviderArguments)
+				if pp.Host == "" {
+					tflog.Debug(ctx, fmt.Sprintf("@diff host is empty. arguments: %+v", pp))
+					return nil
+				}
+				client, err := newAlternator(oldDatabase.(string), pp)
+				if err != nil {
+					tflog.Debug(ctx, fmt.Sprintf("@diff failed to initialize alternator: %s", err.Error()))
+					return nil
+				}
+				defer client.Close()
+				changes, readOnly, err := databaseRenameChanges(client.Db, oldDatabase.(string), database.(string))
+				if err != nil {
+					return err
+				}
+				if changes != nil {
+					// The schema is compared with the old database, because the new one does not exist until applied
+					oldSchemaStr, schemaStr := d.GetChange("schema")
+					renamedSchemaStr, err := renamedSchemaString(client, schemaStr.(string), database.(string), oldDatabase.(string))
+					if err != nil {
+						return err
+					}
+					diff, err := getSchemaDiff(client, oldDatabase.(string), renamedSchemaStr, oldSchemaStr.(string), expandRenames(d.Get("renames")), expandStringList(d.Get("ignore_partitions")))
+					if err != nil {
+						return err
+					}
+					err = checkDriftPolicy(d, diff.Statements())
+					if err != nil {
+						return err
+					}
+					changes = append(changes, renamedPlannedChanges(diff.Changes, oldDatabase.(string), database.(string))...)
+					if readOnly {
+						changes = append(changes, readOnlyChange(oldDatabase.(string), database.(string)))
+					}
+					tflog.Debug(ctx, fmt.Sprintf("@diff rename statements: %s", plannedChangeStatements(changes)))
+					for _, k := range []string{"remote_schema", "tables"} {
+						err = d.SetNewComputed(k)
+						if err != nil {
+							return err
+						}
+					}
+					err = d.SetNew("statements", plannedChangeStatements(changes))
+					if err != nil {
+						return err
+					}
+					err = d.SetNew("planned_changes", flattenPlannedChanges(changes))
+					if err != nil {
+						return err
+					}
+					return d.SetNew("change_summary", changeSummary(changes))
+				}
+			}
+
 			if localSchemaChanged || remoteSchemaChanged {
 				database := d.Get("database").(string)
 				schemaStr := d.Get("schema").(string)
@@ -78,20 +185,30 @@ func resourceAlternatorDatabaseSchema() *schema.Resource {
 					tflog.Debug(ctx, fmt.Sprintf("@diff failed to initialize alternator: %s", err.Error()))
 					return nil
 				}
+				defer client.Close()
 				// Read local schema
-				alt, _, _, err := client.GetAlterations(schemaStr)
+				oldSchemaStr, _ := d.GetChange("schema")
+				diff, err := getSchemaDiff(client, database, schemaStr, oldSchemaStr.(string), expandRenames(d.Get("renames")), expandStringList(d.Get("ignore_partitions")))
 				if err != nil {
 					return err
 				}
 				// Use local schema as new remote schema value to show diff on planing
-				newRemoteSchemaStr := ""
-				for _, s := range alt.ToString() {
-					newRemoteSchemaStr += fmt.Sprintf("%s\n", s)
-				}
-				statements := alt.Statements()
+				newRemoteSchemaStr := diff.LocalSchemaString()
+				changes := diff.Changes
+				statements := diff.Statements()
 				tflog.Debug(ctx, fmt.Sprintf("@diff remote_schema: %s", newRemoteSchemaStr))
 				tflog.Debug(ctx, fmt.Sprintf("@diff statements: %s", statements))
-				err = d.SetNew("remote_schema", newRemoteSchemaStr)
+
+				err = checkDriftPolicy(d, statements)
+				if err != nil {
+					return err
+				}
+				// Definitions of schema objects are rewritten by the server, so they are unknown until applied
+				if diff.HasObjectChanges() {
+					err = d.SetNewComputed("remote_schema")
+				} else {
+					err = d.SetNew("remote_schema", newRemoteSchemaStr)
+				}
 				if err != nil {
 					return err
 				}
@@ -100,6 +217,18 @@ func resourceAlternatorDatabaseSchema() *schema.Resource {
 				if err != nil {
 					return err
 				}
+				err = d.SetNew("planned_changes", flattenPlannedChanges(changes))
+				if err != nil {
+					return err
+				}
+				err = d.SetNew("change_summary", changeSummary(changes))
+				if err != nil {
+					return err
+				}
+				err = d.SetNew("tables", flattenSchemaTables(diff.LocalSchemas))
+				if err != nil {
+					return err
+				}
 			}
 
 			tflog.Debug(ctx, fmt.Sprintf("@diff end"))
@@ -108,13 +237,37 @@ func resourceAlternatorDatabaseSchema() *schema.Resource {
 	}
 }
 
+// checkDriftPolicy refuses to revert the drift if the policy is "error", since it might be an emergency hotfix.
+func checkDriftPolicy(d *schema.ResourceDiff, statements []string) error {
+	if d.Get("drift_policy").(string) != "error" {
+		return nil
+	}
+	driftStatements := map[string]bool{}
+	for _, s := range d.Get("drift_statements").([]interface{}) {
+		driftStatements[s.(string)] = true
+	}
+	for _, s := range statements {
+		if driftStatements[s] {
+			return fmt.Errorf("remote database schema has been changed outside of Terraform and drift_policy is \"error\". "+
+				"Update the schema argument to match the remote database, or change drift_policy to revert it. statement: %s", s)
+		}
+	}
+	return nil
+}
+
 func resourceAlternatorDatabaseSchemaCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	tflog.Debug(ctx, fmt.Sprintf("@create start"))
 
 	database := d.Get("database").(string)
 	schemaStr := d.Get("schema").(string)
+	data := expandInitialData(d.Get("initial_data"))
 	pp := meta.(*ProviderArguments)
 
+	err := validateInitialData(data)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
 	client, err := newAlternator(database, pp)
 	if err != nil {
 		return diag.FromErr(err)
@@ -122,30 +275,31 @@ func resourceAlternatorDatabaseSchemaCreate(ctx context.Context, d *schema.Resou
 	defer client.Close()
 
 	// Create remote database
-	statements := []string{}
-	for _, s := range strings.Split(schemaStr, ";") {
-		trimmed := strings.TrimSpace(s)
-		if trimmed != "" {
-			statements = append(statements, trimmed)
-		}
+	err = execStatements(ctx, client.Db, database, splitStatements(schemaStr), "@create")
+	if err != nil {
+		return diag.FromErr(err)
 	}
-	for _, s := range statements {
-		tflog.Info(ctx, fmt.Sprintf("@create executing statements: %s", s))
-		_, err := client.Db.Exec(s)
-		if err != nil {
-			return diag.FromErr(err)
-		}
+
+	// Track the database before loading initial data, so that it is tainted and recreated if the load fails
+	d.SetId(database)
+
+	// Load initial data only once, never on update
+	rowCounts, err := loadInitialData(ctx, client.Db, database, data)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	tflog.Debug(ctx, fmt.Sprintf("@create initial_data_rows: %+v", rowCounts))
+	err = d.Set("initial_data_rows", rowCounts)
+	if err != nil {
+		return diag.FromErr(err)
 	}
 
 	// Fetch current remote database schemas
-	alt, _, _, err := client.GetAlterations(schemaStr)
+	diff, err := getSchemaDiff(client, database, schemaStr, schemaStr, nil, expandStringList(d.Get("ignore_partitions")))
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	remoteSchemaStr := ""
-	for _, s := range alt.FromString() {
-		remoteSchemaStr += fmt.Sprintf("%s\n", s)
-	}
+	remoteSchemaStr := diff.RemoteSchemaString()
 	tflog.Debug(ctx, fmt.Sprintf("@create remote_schema: %s", remoteSchemaStr))
 
 	err = d.Set("remote_schema", remoteSchemaStr)
@@ -156,11 +310,26 @@ func resourceAlternatorDatabaseSchemaCreate(ctx context.Context, d *schema.Resou
 	if err != nil {
 		diag.FromErr(err)
 	}
+	err = d.Set("drift_statements", []string{})
+	if err != nil {
+		return diag.FromErr(err)
+	}
 	err = d.Set("statements", []string{})
 	if err != nil {
 		diag.FromErr(err)
 	}
-	d.SetId(database)
+	err = d.Set("planned_changes", []interface{}{})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	err = d.Set("change_summary", changeSummary(nil))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	err = d.Set("tables", flattenSchemaTables(diff.RemoteSchemas))
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
 	tflog.Debug(ctx, fmt.Sprintf("@create end"))
 	return nil
@@ -186,20 +355,23 @@ func resourceAlternatorDatabaseSchemaRead(ctx context.Context, d *schema.Resourc
 	defer client.Close()
 
 	// Fetch current remote database schemas
-	alt, _, _, err := client.GetAlterations(schemaStr)
+	diff, err := getSchemaDiff(client, database, schemaStr, schemaStr, nil, expandStringList(d.Get("ignore_partitions")))
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	remoteSchemaStr := ""
-	for _, s := range alt.FromString() {
-		remoteSchemaStr += fmt.Sprintf("%s\n", s)
-	}
+	remoteSchemaStr := diff.RemoteSchemaString()
 
-	changed := len(alt.Statements()) > 0
+	driftStatements := diff.Statements()
+	changed := len(driftStatements) > 0
 
 	tflog.Debug(ctx, fmt.Sprintf("@read remote_schema: %s", remoteSchemaStr))
 	tflog.Debug(ctx, fmt.Sprintf("@read changed: %t", changed))
 
+	var diags diag.Diagnostics
+	if len(driftStatements) > 0 {
+		diags = append(diags, driftWarning(database, diff.Changes))
+	}
+
 	err = d.Set("remote_schema", remoteSchemaStr)
 	if err != nil {
 		diag.FromErr(err)
@@ -208,14 +380,30 @@ func resourceAlternatorDatabaseSchemaRead(ctx context.Context, d *schema.Resourc
 	if err != nil {
 		diag.FromErr(err)
 	}
+	err = d.Set("drift_statements", driftStatements)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 	err = d.Set("statements", []string{})
 	if err != nil {
 		diag.FromErr(err)
 	}
+	err = d.Set("planned_changes", []interface{}{})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	err = d.Set("change_summary", changeSummary(nil))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	err = d.Set("tables", flattenSchemaTables(diff.RemoteSchemas))
+	if err != nil {
+		return diag.FromErr(err)
+	}
 	d.SetId(database)
 
 	tflog.Debug(ctx, fmt.Sprintf("@read end"))
-	return nil
+	return diags
 }
 
 func resourceAlternatorDatabaseSchemaUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -231,28 +419,50 @@ func resourceAlternatorDatabaseSchemaUpdate(ctx context.Context, d *schema.Resou
 	}
 	defer client.Close()
 
-	// Update remote database schemas
-	alt, _, _, err := client.GetAlterations(schemaStr)
-	if err != nil {
-		return diag.FromErr(err)
+	// Rename remote database before applying other changes
+	var readOnly bool
+	oldDatabase, _ := d.GetChange("database")
+	if d.HasChange("database") {
+		var changes []*plannedChange
+		changes, readOnly, err = databaseRenameChanges(client.Db, oldDatabase.(string), database)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		err = execStatements(ctx, client.Db, database, plannedChangeStatements(changes), "@update")
+		if err != nil {
+			return diag.FromErr(err)
+		}
 	}
-	for _, s := range alt.Statements() {
-		tflog.Info(ctx, fmt.Sprintf("@update executing statements: %s", s))
-		_, err := client.Db.Exec(s)
+
+	// Update remote database schemas.
+	// Drift is not reverted if the policy is "warn", unless the schema itself has been changed.
+	if d.HasChange("schema") || d.HasChange("renames") || d.HasChange("ignore_partitions") || d.HasChange("database") || d.Get("drift_policy").(string) != "warn" {
+		oldSchemaStr, _ := d.GetChange("schema")
+		diff, err := getSchemaDiff(client, database, schemaStr, oldSchemaStr.(string), expandRenames(d.Get("renames")), expandStringList(d.Get("ignore_partitions")))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		err = execStatements(ctx, client.Db, database, diff.Statements(), "@update")
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	// The renamed database is made read only after all the changes, since tables cannot be altered in it
+	if readOnly {
+		err = execStatements(ctx, client.Db, database, []string{readOnlyChange(oldDatabase.(string), database).Sql}, "@update")
 		if err != nil {
 			return diag.FromErr(err)
 		}
 	}
 
 	// Fetch current remote database schemas
-	alt, _, _, err = client.GetAlterations(schemaStr)
+	diff, err := getSchemaDiff(client, database, schemaStr, schemaStr, nil, expandStringList(d.Get("ignore_partitions")))
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	remoteSchemaStr := ""
-	for _, s := range alt.FromString() {
-		remoteSchemaStr += fmt.Sprintf("%s\n", s)
-	}
+	remoteSchemaStr := diff.RemoteSchemaString()
+	// Remaining statements are the drift left by the "warn" policy
+	driftStatements := diff.Statements()
 
 	tflog.Debug(ctx, fmt.Sprintf("@update remote_schema: %s", remoteSchemaStr))
 
@@ -260,14 +470,30 @@ func resourceAlternatorDatabaseSchemaUpdate(ctx context.Context, d *schema.Resou
 	if err != nil {
 		diag.FromErr(err)
 	}
-	err = d.Set("changed", false)
+	err = d.Set("changed", len(driftStatements) > 0)
 	if err != nil {
 		diag.FromErr(err)
 	}
+	err = d.Set("drift_statements", driftStatements)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 	err = d.Set("statements", []string{})
 	if err != nil {
 		diag.FromErr(err)
 	}
+	err = d.Set("planned_changes", []interface{}{})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	err = d.Set("change_summary", changeSummary(nil))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	err = d.Set("tables", flattenSchemaTables(diff.RemoteSchemas))
+	if err != nil {
+		return diag.FromErr(err)
+	}
 	d.SetId(database)
 
 	tflog.Debug(ctx, fmt.Sprintf("@update end"))
@@ -306,16 +532,83 @@ func resourceAlternatorDatabaseSchemaDelete(ctx context.Context, d *schema.Resou
 }
 
 func resourceAlternatorDatabaseSchemaImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
-	database := d.Id()
-	tflog.Debug(ctx, fmt.Sprintf("@import id = %s", database))
-	err := d.Set("database", database)
+	id := d.Id()
+	tflog.Debug(ctx, fmt.Sprintf("@import id = %s", id))
+
+	database := id
+	pp := meta.(*ProviderArguments)
+	// ID can be prefixed by host to distinguish databases managed by multiple providers, like "host/database"
+	if i := strings.LastIndex(id, "/"); i >= 0 {
+		host := id[:i]
+		database = id[i+1:]
+		if host != pp.Host {
+			return nil, fmt.Errorf("host %s of the import ID does not match the provider host %s", host, pp.Host)
+		}
+	}
+
+	client, err := newAlternator(database, pp)
 	if err != nil {
-		diag.FromErr(err)
+		return nil, err
+	}
+	defer client.Close()
+
+	// Use the current remote database schema as the schema argument, so that the next plan has no changes
+	remoteSchemas, err := client.FetchSchemas()
+	if err != nil {
+		return nil, err
+	}
+	if len(remoteSchemas) == 0 {
+		return nil, fmt.Errorf("database %s does not exist", database)
+	}
+	remoteObjects, err := fetchSchemaObjects(client.Db, database)
+	if err != nil {
+		return nil, err
+	}
+	remoteSchemaStr := ""
+	for _, s := range remoteSchemas {
+		remoteSchemaStr += fmt.Sprintf("%s\n", s)
+	}
+	remoteSchemaStr += formatSchemaObjects(remoteObjects)
+	tflog.Debug(ctx, fmt.Sprintf("@import remote_schema: %s", remoteSchemaStr))
+
+	err = d.Set("database", database)
+	if err != nil {
+		return nil, err
+	}
+	err = d.Set("schema", remoteSchemaStr)
+	if err != nil {
+		return nil, err
+	}
+	err = d.Set("remote_schema", remoteSchemaStr)
+	if err != nil {
+		return nil, err
+	}
+	err = d.Set("drift_policy", "revert")
+	if err != nil {
+		return nil, err
 	}
 	d.SetId(database)
 	return []*schema.ResourceData{d}, nil
 }
 
+// driftWarning builds a warning diagnostic listing the objects changed outside of Terraform.
+func driftWarning(database string, changes []*plannedChange) diag.Diagnostic {
+	objects := []string{}
+	seen := map[string]bool{}
+	for _, c := range changes {
+		if seen[c.Reason] {
+			continue
+		}
+		seen[c.Reason] = true
+		objects = append(objects, fmt.Sprintf("  - %s", c.Reason))
+	}
+	return diag.Diagnostic{
+		Severity: diag.Warning,
+		Summary:  fmt.Sprintf("Database %s has been changed outside of Terraform", database),
+		Detail:   fmt.Sprintf("The following differences from the schema argument have been detected:\n%s", strings.Join(objects, "\n")),
+	}
+}
+
 func newAlternator(database string, p *ProviderArguments) (*cmd.Alternator, error) {
 	dbUri := &cmd.DatabaseUri{
 		Dialect:  p.Dialect,
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/stretchr/testify/require"
+	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
@@ -53,7 +55,22 @@ func TestAccResourceAlternatorDatabaseSchema(t *testing.T) {
 				Config: testAccResourceAlternatorDatabaseSchemaInitialConfig(),
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr("alternator_database_schema.main", "id", "example"),
+					resource.TestCheckResourceAttr("alternator_database_schema.main", "change_summary", "no changes"),
 					resource.TestCheckResourceAttr("alternator_database_schema.main", "remote_schema", initialSchemaRemote),
+					resource.TestCheckResourceAttr("alternator_database_schema.main", "tables.#", "1"),
+					resource.TestCheckResourceAttr("alternator_database_schema.main", "tables.0.name", "greeting"),
+					resource.TestCheckResourceAttr("alternator_database_schema.main", "tables.0.engine", "InnoDB"),
+					resource.TestCheckResourceAttr("alternator_database_schema.main", "tables.0.collation", "utf8mb4_0900_bin"),
+					resource.TestCheckResourceAttr("alternator_database_schema.main", "tables.0.columns.#", "4"),
+					resource.TestCheckResourceAttr("alternator_database_schema.main", "tables.0.columns.0.name", "id"),
+					resource.TestCheckResourceAttr("alternator_database_schema.main", "tables.0.columns.0.nullable", "false"),
+					resource.TestCheckResourceAttr("alternator_database_schema.main", "tables.0.columns.0.extra", "auto_increment"),
+					resource.TestCheckResourceAttr("alternator_database_schema.main", "tables.0.columns.1.type", "varchar(255)"),
+					resource.TestCheckResourceAttr("alternator_database_schema.main", "tables.0.columns.1.nullable", "true"),
+					resource.TestCheckResourceAttr("alternator_database_schema.main", "tables.0.indexes.#", "1"),
+					resource.TestCheckResourceAttr("alternator_database_schema.main", "tables.0.indexes.0.name", "PRIMARY"),
+					resource.TestCheckResourceAttr("alternator_database_schema.main", "tables.0.indexes.0.columns.0", "id"),
+					resource.TestCheckResourceAttr("alternator_database_schema.main", "tables.0.foreign_keys.#", "0"),
 				),
 			},
 			// Update schema
@@ -62,6 +79,7 @@ func TestAccResourceAlternatorDatabaseSchema(t *testing.T) {
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr("alternator_database_schema.main", "id", "example"),
 					resource.TestCheckResourceAttr("alternator_database_schema.main", "remote_schema", updatedSchemaRemote),
+					resource.TestCheckResourceAttr("alternator_database_schema.main", "tables.0.columns.1.type", "varchar(256)"),
 				),
 			},
 			// Change schema from outside
@@ -90,11 +108,49 @@ func TestAccResourceAlternatorDatabaseSchema(t *testing.T) {
 				ImportStateIdFunc: func(state *terraform.State) (string, error) {
 					return "example2", nil
 				},
+				ImportStateCheck: func(states []*terraform.InstanceState) error {
+					if len(states) != 1 {
+						return fmt.Errorf("expected 1 state: %d", len(states))
+					}
+					if states[0].Attributes["database"] != "example2" {
+						return fmt.Errorf("unexpected database: %s", states[0].Attributes["database"])
+					}
+					// Schema must be populated not to drop all tables on the next apply
+					if states[0].Attributes["schema"] != anotherSchemaRemote {
+						return fmt.Errorf("unexpected schema: %s", states[0].Attributes["schema"])
+					}
+					return nil
+				},
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr("alternator_database_schema.main", "id", "example2"),
 					resource.TestCheckResourceAttr("alternator_database_schema.main", "remote_schema", anotherSchemaRemote),
 				),
 			},
+			// Import another database schema with host
+			{
+				Config:       testAccResourceAlternatorDatabaseSchemaAnotherConfig(),
+				ResourceName: "alternator_database_schema.main",
+				ImportState:  true,
+				ImportStateIdFunc: func(state *terraform.State) (string, error) {
+					return "localhost:23306/example2", nil
+				},
+				ImportStateCheck: func(states []*terraform.InstanceState) error {
+					if states[0].ID != "example2" {
+						return fmt.Errorf("unexpected id: %s", states[0].ID)
+					}
+					return nil
+				},
+			},
+			// Import with another host
+			{
+				Config:       testAccResourceAlternatorDatabaseSchemaAnotherConfig(),
+				ResourceName: "alternator_database_schema.main",
+				ImportState:  true,
+				ImportStateIdFunc: func(state *terraform.State) (string, error) {
+					return "otherhost:3306/example2", nil
+				},
+				ExpectError: regexp.MustCompile("does not match the provider host"),
+			},
 			// Recreate
 			{
 				Config: testAccResourceAlternatorDatabaseSchemaInitialConfig(),
@@ -107,6 +163,343 @@ func TestAccResourceAlternatorDatabaseSchema(t *testing.T) {
 	})
 }
 
+func TestAccResourceAlternatorDatabaseSchemaDriftPolicy(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			// Create
+			{
+				PreConfig: func() {
+					db, err := sql.Open("mysql", "root@tcp(localhost:23306)/")
+					require.NoError(t, err)
+					_, err = db.Exec("DROP DATABASE IF EXISTS example")
+					require.NoError(t, err)
+				},
+				Config: testAccResourceAlternatorDatabaseSchemaDriftPolicyConfig("error"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("alternator_database_schema.main", "drift_policy", "error"),
+					resource.TestCheckResourceAttr("alternator_database_schema.main", "drift_statements.#", "0"),
+				),
+			},
+			// Change schema from outside, which must not be reverted
+			{
+				PreConfig: func() {
+					db, err := sql.Open("mysql", "root@tcp(localhost:23306)/")
+					require.NoError(t, err)
+					_, err = db.Exec("ALTER TABLE example.greeting MODIFY COLUMN body varchar(200)")
+					require.NoError(t, err)
+				},
+				Config:      testAccResourceAlternatorDatabaseSchemaDriftPolicyConfig("error"),
+				ExpectError: regexp.MustCompile("drift_policy is \"error\""),
+			},
+			// Only warn the drift
+			{
+				Config: testAccResourceAlternatorDatabaseSchemaDriftPolicyConfig("warn"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("alternator_database_schema.main", "drift_statements.#", "1"),
+					resource.TestCheckResourceAttr("alternator_database_schema.main", "tables.0.columns.1.type", "varchar(200)"),
+				),
+			},
+			// Revert the drift
+			{
+				Config: testAccResourceAlternatorDatabaseSchemaDriftPolicyConfig("revert"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("alternator_database_schema.main", "drift_statements.#", "0"),
+					resource.TestCheckResourceAttr("alternator_database_schema.main", "remote_schema", initialSchemaRemote),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceAlternatorDatabaseSchemaRenames(t *testing.T) {
+	renamedSchema := strings.Replace(initialSchema, "body       varchar(255)", "message    varchar(255)", 1)
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			// Create
+			{
+				PreConfig: func() {
+					db, err := sql.Open("mysql", "root@tcp(localhost:23306)/")
+					require.NoError(t, err)
+					_, err = db.Exec("DROP DATABASE IF EXISTS example")
+					require.NoError(t, err)
+				},
+				Config: testAccResourceAlternatorDatabaseSchemaRenamesConfig(initialSchema),
+			},
+			// Rename column
+			{
+				PreConfig: func() {
+					db, err := sql.Open("mysql", "root@tcp(localhost:23306)/")
+					require.NoError(t, err)
+					_, err = db.Exec("INSERT INTO example.greeting (body) VALUES ('hello')")
+					require.NoError(t, err)
+				},
+				Config: testAccResourceAlternatorDatabaseSchemaRenamesConfig(renamedSchema),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("alternator_database_schema.main", "tables.0.columns.1.name", "message"),
+					func(state *terraform.State) error {
+						db, err := sql.Open("mysql", "root@tcp(localhost:23306)/")
+						if err != nil {
+							return err
+						}
+						var message string
+						err = db.QueryRow("SELECT message FROM example.greeting").Scan(&message)
+						if err != nil {
+							return err
+						}
+						if message != "hello" {
+							return fmt.Errorf("renamed column lost its data: %s", message)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceAlternatorDatabaseSchemaObjects(t *testing.T) {
+	objects := `
+CREATE VIEW greeting_bodies AS SELECT id, body FROM greeting;
+DELIMITER //
+CREATE TRIGGER greeting_bi BEFORE INSERT ON greeting FOR EACH ROW
+BEGIN
+    SET NEW.body = TRIM(NEW.body);
+END//
+DELIMITER ;
+`
+	updatedObjects := strings.Replace(objects, "SELECT id, body", "SELECT body", 1)
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			// Create
+			{
+				PreConfig: func() {
+					db, err := sql.Open("mysql", "root@tcp(localhost:23306)/")
+					require.NoError(t, err)
+					_, err = db.Exec("DROP DATABASE IF EXISTS example")
+					require.NoError(t, err)
+				},
+				Config: testAccResourceAlternatorDatabaseSchemaObjectsConfig(initialSchema + objects),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("alternator_database_schema.main", "remote_schema", regexp.MustCompile("VIEW `greeting_bodies`")),
+					resource.TestMatchResourceAttr("alternator_database_schema.main", "remote_schema", regexp.MustCompile("TRIGGER `greeting_bi`")),
+				),
+			},
+			// Replace view
+			{
+				Config: testAccResourceAlternatorDatabaseSchemaObjectsConfig(initialSchema + updatedObjects),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("alternator_database_schema.main", "remote_schema", regexp.MustCompile("select `example`.`greeting`.`body` AS `body`")),
+				),
+			},
+			// Dropped outside of Terraform
+			{
+				PreConfig: func() {
+					db, err := sql.Open("mysql", "root@tcp(localhost:23306)/")
+					require.NoError(t, err)
+					_, err = db.Exec("DROP TRIGGER example.greeting_bi")
+					require.NoError(t, err)
+				},
+				Config:             testAccResourceAlternatorDatabaseSchemaObjectsConfig(initialSchema + updatedObjects),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			// Drop objects
+			{
+				Config: testAccResourceAlternatorDatabaseSchemaObjectsConfig(initialSchema),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("alternator_database_schema.main", "remote_schema", initialSchemaRemote),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceAlternatorDatabaseSchemaInitialData(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			// Create
+			{
+				PreConfig: func() {
+					db, err := sql.Open("mysql", "root@tcp(localhost:23306)/")
+					require.NoError(t, err)
+					_, err = db.Exec("DROP DATABASE IF EXISTS example")
+					require.NoError(t, err)
+				},
+				Config: testAccResourceAlternatorDatabaseSchemaInitialDataConfig(initialSchema, "test/initial_data.sql"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("alternator_database_schema.main", "initial_data_rows.test/initial_data.sql", "2"),
+					resource.TestCheckResourceAttr("alternator_database_schema.main", "initial_data_rows.test/initial_data.csv", "3"),
+					testAccCheckGreetingCount(t, 5),
+				),
+			},
+			// Not loaded again on update
+			{
+				Config: testAccResourceAlternatorDatabaseSchemaInitialDataConfig(updatedSchema, "test/initial_data.sql"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGreetingCount(t, 5),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceAlternatorDatabaseSchemaInitialDataFailure(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			// Failed to load
+			{
+				PreConfig: func() {
+					db, err := sql.Open("mysql", "root@tcp(localhost:23306)/")
+					require.NoError(t, err)
+					_, err = db.Exec("DROP DATABASE IF EXISTS example")
+					require.NoError(t, err)
+				},
+				Config:      testAccResourceAlternatorDatabaseSchemaInitialDataConfig(initialSchema, "test/initial_data_broken.sql"),
+				ExpectError: regexp.MustCompile("missing_table"),
+			},
+			// The tainted database is recreated
+			{
+				Config: testAccResourceAlternatorDatabaseSchemaInitialDataConfig(initialSchema, "test/initial_data.sql"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("alternator_database_schema.main", "id", "example"),
+					testAccCheckGreetingCount(t, 5),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceAlternatorDatabaseSchemaRenameDatabase(t *testing.T) {
+	renamedSchema := strings.ReplaceAll(initialSchema, "example", "example_renamed")
+	movedSchema := strings.ReplaceAll(updatedSchema, "example", "example_moved")
+	readOnlySchema := strings.ReplaceAll(updatedSchema, "example", "example_read_only")
+	view := "CREATE VIEW greeting_bodies AS SELECT id, body FROM greeting;\n"
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			// Create
+			{
+				PreConfig: func() {
+					db, err := sql.Open("mysql", "root@tcp(localhost:23306)/")
+					require.NoError(t, err)
+					_, err = db.Exec("DROP DATABASE IF EXISTS example")
+					require.NoError(t, err)
+					_, err = db.Exec("DROP DATABASE IF EXISTS example_renamed")
+					require.NoError(t, err)
+					_, err = db.Exec("DROP DATABASE IF EXISTS example_moved")
+					require.NoError(t, err)
+					_, err = db.Exec("DROP DATABASE IF EXISTS example_read_only")
+					require.NoError(t, err)
+				},
+				Config: testAccResourceAlternatorDatabaseSchemaObjectsConfig(initialSchema + view),
+			},
+			// Rename
+			{
+				PreConfig: func() {
+					db, err := sql.Open("mysql", "root@tcp(localhost:23306)/")
+					require.NoError(t, err)
+					_, err = db.Exec("INSERT INTO example.greeting (body) VALUES ('hello')")
+					require.NoError(t, err)
+				},
+				Config: strings.Replace(testAccResourceAlternatorDatabaseSchemaObjectsConfig(renamedSchema+view), `database = "example"`, `database = "example_renamed"`, 1),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("alternator_database_schema.main", "id", "example_renamed"),
+					resource.TestMatchResourceAttr("alternator_database_schema.main", "remote_schema", regexp.MustCompile("VIEW `greeting_bodies`")),
+					// Rows are kept, so the database is not recreated
+					testAccCheckGreetingCountIn(t, "example_renamed", 1),
+				),
+			},
+			// Rename with schema changes
+			{
+				Config: strings.Replace(testAccResourceAlternatorDatabaseSchemaObjectsConfig(movedSchema+view), `database = "example"`, `database = "example_moved"`, 1),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("alternator_database_schema.main", "id", "example_moved"),
+					resource.TestMatchResourceAttr("alternator_database_schema.main", "remote_schema", regexp.MustCompile("varchar\\(256\\)")),
+					testAccCheckGreetingCountIn(t, "example_moved", 1),
+				),
+			},
+			// Rename read-only database
+			{
+				PreConfig: func() {
+					db, err := sql.Open("mysql", "root@tcp(localhost:23306)/")
+					require.NoError(t, err)
+					_, err = db.Exec("ALTER SCHEMA example_moved READ ONLY = 1")
+					require.NoError(t, err)
+				},
+				Config: strings.Replace(testAccResourceAlternatorDatabaseSchemaObjectsConfig(readOnlySchema+view), `database = "example"`, `database = "example_read_only"`, 1),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("alternator_database_schema.main", "id", "example_read_only"),
+					testAccCheckGreetingCountIn(t, "example_read_only", 1),
+					func(state *terraform.State) error {
+						db, err := sql.Open("mysql", "root@tcp(localhost:23306)/")
+						require.NoError(t, err)
+						var options string
+						err = db.QueryRow("SELECT OPTIONS FROM information_schema.SCHEMATA_EXTENSIONS WHERE SCHEMA_NAME = 'example_read_only'").Scan(&options)
+						require.NoError(t, err)
+						if !strings.Contains(options, "READ ONLY=1") {
+							return fmt.Errorf("renamed database is expected to be read only, but got options: %s", options)
+						}
+						return nil
+					},
+				),
+			},
+			// Make it writable to be destroyed
+			{
+				PreConfig: func() {
+					db, err := sql.Open("mysql", "root@tcp(localhost:23306)/")
+					require.NoError(t, err)
+					_, err = db.Exec("ALTER SCHEMA example_read_only READ ONLY = 0")
+					require.NoError(t, err)
+				},
+				Config:   strings.Replace(testAccResourceAlternatorDatabaseSchemaObjectsConfig(readOnlySchema+view), `database = "example"`, `database = "example_read_only"`, 1),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func testAccCheckGreetingCount(t *testing.T, expected int) resource.TestCheckFunc {
+	return testAccCheckGreetingCountIn(t, "example", expected)
+}
+
+func testAccCheckGreetingCountIn(t *testing.T, database string, expected int) resource.TestCheckFunc {
+	return func(state *terraform.State) error {
+		db, err := sql.Open("mysql", "root@tcp(localhost:23306)/")
+		require.NoError(t, err)
+		var count int
+		err = db.QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM `%s`.greeting", database)).Scan(&count)
+		require.NoError(t, err)
+		if count != expected {
+			return fmt.Errorf("expected %d rows, but got %d", expected, count)
+		}
+		return nil
+	}
+}
+
+func testAccResourceAlternatorDatabaseSchemaInitialDataConfig(schema string, sqlFile string) string {
+	return fmt.Sprintf(`
+    %s
+	resource "alternator_database_schema" "main" {
+        database = "example"
+        schema = <<EOT
+		%s
+		EOT
+        initial_data {
+            sql_file = "%s"
+        }
+        initial_data {
+            csv_file = "test/initial_data.csv"
+            table    = "greeting"
+        }
+	}
+	`, provider, schema, sqlFile)
+}
+
 func testAccResourceAlternatorDatabaseSchemaInitialConfig() string {
 	return fmt.Sprintf(`
     %s
@@ -142,3 +535,45 @@ func testAccResourceAlternatorDatabaseSchemaAnotherConfig() string {
 	}
 	`, provider, anotherSchema)
 }
+
+func testAccResourceAlternatorDatabaseSchemaDriftPolicyConfig(policy string) string {
+	return fmt.Sprintf(`
+    %s
+	resource "alternator_database_schema" "main" {
+        database     = "example"
+        drift_policy = "%s"
+        schema = <<EOT
+		%s
+		EOT
+	}
+	`, provider, policy, initialSchema)
+}
+
+func testAccResourceAlternatorDatabaseSchemaRenamesConfig(schema string) string {
+	return fmt.Sprintf(`
+    %s
+	resource "alternator_database_schema" "main" {
+        database = "example"
+        renames {
+            table = "greeting"
+            from  = "body"
+            to    = "message"
+        }
+        schema = <<EOT
+		%s
+		EOT
+	}
+	`, provider, schema)
+}
+
+func testAccResourceAlternatorDatabaseSchemaObjectsConfig(schema string) string {
+	return fmt.Sprintf(`
+    %s
+	resource "alternator_database_schema" "main" {
+        database = "example"
+        schema = <<EOT
+		%s
+		EOT
+	}
+	`, provider, schema)
+}
@@ -0,0 +1,91 @@
+package provider
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/stretchr/testify/require"
+	"regexp"
+	"testing"
+)
+
+func TestAccResourceAlternatorDatabase(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			// Create
+			{
+				PreConfig: func() {
+					db, err := sql.Open("mysql", "root@tcp(localhost:23306)/")
+					require.NoError(t, err)
+					_, err = db.Exec("DROP DATABASE IF EXISTS example4")
+					require.NoError(t, err)
+				},
+				Config: testAccResourceAlternatorDatabaseConfig(`character_set = "utf8mb4"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("alternator_database.main", "id", "example4"),
+					resource.TestCheckResourceAttr("alternator_database.main", "character_set", "utf8mb4"),
+					resource.TestCheckResourceAttr("alternator_database.main", "collation", "utf8mb4_0900_ai_ci"),
+					resource.TestCheckResourceAttr("alternator_database.main", "read_only", "false"),
+				),
+			},
+			// Update options
+			{
+				Config: testAccResourceAlternatorDatabaseConfig(`
+					character_set = "utf8mb4"
+					collation     = "utf8mb4_bin"
+					read_only     = true
+				`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("alternator_database.main", "collation", "utf8mb4_bin"),
+					resource.TestCheckResourceAttr("alternator_database.main", "read_only", "true"),
+				),
+			},
+			// Change character set without collation
+			{
+				Config: testAccResourceAlternatorDatabaseConfig(`character_set = "latin1"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("alternator_database.main", "character_set", "latin1"),
+					resource.TestCheckResourceAttr("alternator_database.main", "collation", "latin1_swedish_ci"),
+					resource.TestCheckResourceAttr("alternator_database.main", "read_only", "false"),
+				),
+			},
+			// Import
+			{
+				Config:            testAccResourceAlternatorDatabaseConfig(`character_set = "latin1"`),
+				ResourceName:      "alternator_database.main",
+				ImportState:       true,
+				ImportStateId:     "example4",
+				ImportStateVerify: true,
+			},
+			// Non-empty database is not destroyed
+			{
+				PreConfig: func() {
+					db, err := sql.Open("mysql", "root@tcp(localhost:23306)/")
+					require.NoError(t, err)
+					_, err = db.Exec("CREATE TABLE example4.users (id int PRIMARY KEY)")
+					require.NoError(t, err)
+				},
+				Config:      provider,
+				ExpectError: regexp.MustCompile("set force_destroy to true"),
+			},
+			// Destroy with force_destroy
+			{
+				Config: testAccResourceAlternatorDatabaseConfig(`
+					character_set = "latin1"
+					force_destroy = true
+				`),
+			},
+		},
+	})
+}
+
+func testAccResourceAlternatorDatabaseConfig(options string) string {
+	return fmt.Sprintf(`
+    %s
+	resource "alternator_database" "main" {
+        name = "example4"
+        %s
+	}
+	`, provider, options)
+}
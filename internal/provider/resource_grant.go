@@ -0,0 +1,487 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/emirpasic/gods/sets/hashset"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+func resourceAlternatorGrant() *schema.Resource {
+	return &schema.Resource{
+		Description: "Manage privileges or roles granted to a user or a role of SQL database. " +
+			"Privileges are managed per privilege level, so use one resource for each combination of `database`, `table` and `columns`.",
+		CreateContext: resourceAlternatorGrantCreate,
+		ReadContext:   resourceAlternatorGrantRead,
+		UpdateContext: resourceAlternatorGrantUpdate,
+		DeleteContext: resourceAlternatorGrantDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceAlternatorGrantImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"user": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "User or role name to grant to.",
+			},
+			"host": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "%",
+				Description: "Host name pattern of the user.",
+			},
+			"database": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"roles"},
+				Description:   "Database name of the privilege level. Use `*` for global privileges.",
+			},
+			"table": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				Default:       "*",
+				ConflictsWith: []string{"roles"},
+				Description:   "Table name of the privilege level. Use `*` for database level privileges.",
+			},
+			"columns": {
+				Type:          schema.TypeSet,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"roles"},
+				Description:   "Column names for column level privileges. The privileges are granted on each of the columns.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"privileges": {
+				Type:         schema.TypeSet,
+				Optional:     true,
+				ExactlyOneOf: []string{"privileges", "roles"},
+				RequiredWith: []string{"database"},
+				Description:  "Privileges to grant such as `SELECT`, in upper case as shown by `SHOW GRANTS`.",
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringMatch(regexp.MustCompile(`^[A-Z_ ]+$`), "privilege must be in upper case, such as SELECT or ALL PRIVILEGES"),
+				},
+			},
+			"roles": {
+				Type:         schema.TypeSet,
+				Optional:     true,
+				ExactlyOneOf: []string{"privileges", "roles"},
+				Description:  "Roles to grant. Specify `role@host` if the host of the role is not `%`. Only these roles are managed, so roles of the same account can be granted by multiple resources.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"grant_option": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether to grant with `GRANT OPTION`, or with `ADMIN OPTION` for roles.",
+			},
+		},
+	}
+}
+
+func resourceAlternatorGrantCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Debug(ctx, fmt.Sprintf("@create start"))
+
+	user := d.Get("user").(string)
+	host := d.Get("host").(string)
+	pp := meta.(*ProviderArguments)
+
+	client, err := newAlternator("", pp)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer client.Close()
+
+	var statement string
+	grantOption := d.Get("grant_option").(bool)
+	if roles := setToStrings(d.Get("roles")); len(roles) > 0 {
+		statement = grantRolesStatement(roles, user, host, grantOption)
+		d.SetId(fmt.Sprintf("%s@%s", user, host))
+	} else {
+		database := d.Get("database").(string)
+		table := d.Get("table").(string)
+		columns := setToStrings(d.Get("columns"))
+		privileges := setToStrings(d.Get("privileges"))
+		if grantOption {
+			privileges = append(privileges, "GRANT OPTION")
+		}
+		statement = fmt.Sprintf("GRANT %s ON %s TO %s", privilegeList(privileges, columns), grantTarget(database, table), quoteAccount(user, host))
+		d.SetId(grantId(user, host, database, table, columns))
+	}
+	tflog.Info(ctx, fmt.Sprintf("@create executing statements: %s", statement))
+	_, err = client.Db.Exec(statement)
+	if err != nil {
+		d.SetId("")
+		return diag.FromErr(err)
+	}
+
+	tflog.Debug(ctx, fmt.Sprintf("@create end"))
+	return resourceAlternatorGrantRead(ctx, d, meta)
+}
+
+func resourceAlternatorGrantRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Debug(ctx, fmt.Sprintf("@read start"))
+
+	user := d.Get("user").(string)
+	host := d.Get("host").(string)
+	pp := meta.(*ProviderArguments)
+	// cf. resourceAlternatorDatabaseSchemaRead
+	if pp.Host == "" {
+		tflog.Debug(ctx, fmt.Sprintf("@read host is empty. arguments: %+v", pp))
+		d.SetId("")
+		return nil
+	}
+
+	client, err := newAlternator("", pp)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer client.Close()
+
+	grants, err := fetchGrants(client.Db, user, host)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	tflog.Debug(ctx, fmt.Sprintf("@read grants: %d", len(grants)))
+
+	var privileges, roles []string
+	var grantOption bool
+	if strings.Contains(d.Id(), ":") {
+		privileges, grantOption = grantedPrivileges(grants, grantTarget(d.Get("database").(string), d.Get("table").(string)), setToStrings(d.Get("columns")))
+	} else {
+		// All the roles are adopted on import
+		roles, grantOption = grantedRoles(grants, setToStrings(d.Get("roles")))
+	}
+	if len(privileges) == 0 && len(roles) == 0 {
+		tflog.Warn(ctx, fmt.Sprintf("@read grant %s not found, removing from state", d.Id()))
+		d.SetId("")
+		return nil
+	}
+
+	if privileges != nil {
+		err = d.Set("privileges", privileges)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	if roles != nil {
+		err = d.Set("roles", roles)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	err = d.Set("grant_option", grantOption)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	tflog.Debug(ctx, fmt.Sprintf("@read end"))
+	return nil
+}
+
+func resourceAlternatorGrantUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Debug(ctx, fmt.Sprintf("@update start"))
+
+	user := d.Get("user").(string)
+	host := d.Get("host").(string)
+	grantOption := d.Get("grant_option").(bool)
+	pp := meta.(*ProviderArguments)
+
+	client, err := newAlternator("", pp)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer client.Close()
+
+	statements := []string{}
+	if d.HasChange("roles") || d.HasChange("grant_option") && len(setToStrings(d.Get("roles"))) > 0 {
+		o, n := d.GetChange("roles")
+		revoked, granted := diffStrings(setToStrings(o), setToStrings(n))
+		// Admin option cannot be revoked alone, so grant all the roles again
+		if d.HasChange("grant_option") {
+			revoked, granted = setToStrings(o), setToStrings(n)
+		}
+		if len(revoked) > 0 {
+			statements = append(statements, revokeRolesStatement(revoked, user, host))
+		}
+		if len(granted) > 0 {
+			statements = append(statements, grantRolesStatement(granted, user, host, grantOption))
+		}
+	} else {
+		target := grantTarget(d.Get("database").(string), d.Get("table").(string))
+		columns := setToStrings(d.Get("columns"))
+		o, n := d.GetChange("privileges")
+		revoked, granted := diffStrings(setToStrings(o), setToStrings(n))
+		if d.HasChange("grant_option") {
+			if grantOption {
+				granted = append(granted, "GRANT OPTION")
+			} else {
+				revoked = append(revoked, "GRANT OPTION")
+			}
+		}
+		if len(revoked) > 0 {
+			statements = append(statements, fmt.Sprintf("REVOKE %s ON %s FROM %s", privilegeList(revoked, columns), target, quoteAccount(user, host)))
+		}
+		if len(granted) > 0 {
+			statements = append(statements, fmt.Sprintf("GRANT %s ON %s TO %s", privilegeList(granted, columns), target, quoteAccount(user, host)))
+		}
+	}
+	for _, s := range statements {
+		tflog.Info(ctx, fmt.Sprintf("@update executing statements: %s", s))
+		_, err := client.Db.Exec(s)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	tflog.Debug(ctx, fmt.Sprintf("@update end"))
+	return resourceAlternatorGrantRead(ctx, d, meta)
+}
+
+func resourceAlternatorGrantDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Debug(ctx, fmt.Sprintf("@delete start"))
+
+	user := d.Get("user").(string)
+	host := d.Get("host").(string)
+	pp := meta.(*ProviderArguments)
+
+	client, err := newAlternator("", pp)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer client.Close()
+
+	var statement string
+	if roles := setToStrings(d.Get("roles")); len(roles) > 0 {
+		statement = revokeRolesStatement(roles, user, host)
+	} else {
+		privileges := setToStrings(d.Get("privileges"))
+		if d.Get("grant_option").(bool) {
+			privileges = append(privileges, "GRANT OPTION")
+		}
+		statement = fmt.Sprintf("REVOKE %s ON %s FROM %s", privilegeList(privileges, setToStrings(d.Get("columns"))), grantTarget(d.Get("database").(string), d.Get("table").(string)), quoteAccount(user, host))
+	}
+	tflog.Info(ctx, fmt.Sprintf("@delete executing statements: %s", statement))
+	_, err = client.Db.Exec(statement)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+
+	tflog.Debug(ctx, fmt.Sprintf("@delete end"))
+	return nil
+}
+
+func resourceAlternatorGrantImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	id := d.Id()
+	tflog.Debug(ctx, fmt.Sprintf("@import id = %s", id))
+
+	user, host, database, table, columns, err := parseGrantId(id)
+	if err != nil {
+		return nil, err
+	}
+	err = d.Set("user", user)
+	if err != nil {
+		return nil, err
+	}
+	err = d.Set("host", host)
+	if err != nil {
+		return nil, err
+	}
+	if database != "" {
+		err = d.Set("database", database)
+		if err != nil {
+			return nil, err
+		}
+		err = d.Set("columns", columns)
+		if err != nil {
+			return nil, err
+		}
+	}
+	err = d.Set("table", table)
+	if err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+// grantId returns ID of the privilege grant in the form of "user@host:database.table(column1,column2)".
+func grantId(user string, host string, database string, table string, columns []string) string {
+	ret := fmt.Sprintf("%s@%s:%s.%s", user, host, database, table)
+	if len(columns) > 0 {
+		ret += fmt.Sprintf("(%s)", strings.Join(columns, ","))
+	}
+	return ret
+}
+
+// parseGrantId parses the ID made by grantId, or "user@host" for role grants.
+func parseGrantId(id string) (string, string, string, string, []string, error) {
+	account, level, isPrivilege := strings.Cut(id, ":")
+	i := strings.LastIndex(account, "@")
+	if i <= 0 || i == len(account)-1 {
+		return "", "", "", "", nil, fmt.Errorf("import ID must be in the form of \"user@host:database.table\" or \"user@host\": %s", id)
+	}
+	user, host := account[:i], account[i+1:]
+	if !isPrivilege {
+		return user, host, "", "*", nil, nil
+	}
+
+	columns := []string{}
+	if l, c, ok := strings.Cut(level, "("); ok {
+		level = l
+		columns = strings.Split(strings.TrimSuffix(c, ")"), ",")
+	}
+	database, table, ok := strings.Cut(level, ".")
+	if !ok || database == "" || table == "" {
+		return "", "", "", "", nil, fmt.Errorf("import ID must be in the form of \"user@host:database.table\" or \"user@host\": %s", id)
+	}
+	return user, host, database, table, columns, nil
+}
+
+// grantedPrivileges returns privileges granted on the target and whether they are granted with GRANT OPTION.
+// If columns are given, only privileges granted on all the columns are returned.
+func grantedPrivileges(grants []*showGrant, target string, columns []string) ([]string, bool) {
+	ret := []string{}
+	grantOption := false
+	for _, g := range grants {
+		if g.Target != target {
+			continue
+		}
+		for _, p := range g.Privileges {
+			// USAGE means no privileges
+			if p.Name == "USAGE" {
+				continue
+			}
+			if len(columns) == 0 && len(p.Columns) == 0 || len(columns) > 0 && containsAll(p.Columns, columns) {
+				ret = append(ret, p.Name)
+			}
+		}
+		grantOption = grantOption || g.GrantOption
+	}
+	sort.Strings(ret)
+	return ret, grantOption
+}
+
+// grantedRoles returns roles granted to the account and whether they are granted with ADMIN OPTION.
+// If roles are given, only them are returned, so that roles granted by the other resources are not regarded as drift.
+func grantedRoles(grants []*showGrant, roles []string) ([]string, bool) {
+	ret := []string{}
+	grantOption := false
+	for _, g := range grants {
+		if g.Target != "" {
+			continue
+		}
+		for _, r := range g.Roles {
+			if len(roles) > 0 && !containsAll(roles, []string{r}) {
+				continue
+			}
+			ret = append(ret, r)
+			grantOption = grantOption || g.GrantOption
+		}
+	}
+	sort.Strings(ret)
+	return ret, grantOption
+}
+
+// privilegeList formats privileges for GRANT or REVOKE statement, such as "SELECT (`id`, `name`), INSERT (`id`, `name`)".
+func privilegeList(privileges []string, columns []string) string {
+	if len(columns) == 0 {
+		return strings.Join(privileges, ", ")
+	}
+	quoted := []string{}
+	for _, c := range columns {
+		quoted = append(quoted, fmt.Sprintf("`%s`", c))
+	}
+	ret := []string{}
+	for _, p := range privileges {
+		// GRANT OPTION is not a column level privilege
+		if p == "GRANT OPTION" {
+			ret = append(ret, p)
+			continue
+		}
+		ret = append(ret, fmt.Sprintf("%s (%s)", p, strings.Join(quoted, ", ")))
+	}
+	return strings.Join(ret, ", ")
+}
+
+func grantRolesStatement(roles []string, user string, host string, adminOption bool) string {
+	ret := fmt.Sprintf("GRANT %s TO %s", quoteRoles(roles), quoteAccount(user, host))
+	if adminOption {
+		ret += " WITH ADMIN OPTION"
+	}
+	return ret
+}
+
+func revokeRolesStatement(roles []string, user string, host string) string {
+	return fmt.Sprintf("REVOKE %s FROM %s", quoteRoles(roles), quoteAccount(user, host))
+}
+
+func quoteRoles(roles []string) string {
+	ret := []string{}
+	for _, r := range roles {
+		ret = append(ret, quoteRole(r))
+	}
+	return strings.Join(ret, ", ")
+}
+
+func setToStrings(v interface{}) []string {
+	ret := []string{}
+	for _, e := range v.(*schema.Set).List() {
+		ret = append(ret, e.(string))
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// diffStrings returns elements only in the old and elements only in the new.
+func diffStrings(o []string, n []string) ([]string, []string) {
+	oldSet := hashset.New()
+	for _, e := range o {
+		oldSet.Add(e)
+	}
+	newSet := hashset.New()
+	for _, e := range n {
+		newSet.Add(e)
+	}
+	removed := []string{}
+	for _, e := range o {
+		if !newSet.Contains(e) {
+			removed = append(removed, e)
+		}
+	}
+	added := []string{}
+	for _, e := range n {
+		if !oldSet.Contains(e) {
+			added = append(added, e)
+		}
+	}
+	return removed, added
+}
+
+func containsAll(s []string, elements []string) bool {
+	set := hashset.New()
+	for _, e := range s {
+		set.Add(e)
+	}
+	for _, e := range elements {
+		if !set.Contains(e) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,102 @@
+package provider
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestAccResourceAlternatorGrant(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			// Create
+			{
+				PreConfig: func() {
+					db, err := sql.Open("mysql", "root@tcp(localhost:23306)/?multiStatements=true")
+					require.NoError(t, err)
+					_, err = db.Exec("DROP USER IF EXISTS 'grantee'@'%'; DROP ROLE IF EXISTS 'reader'; DROP ROLE IF EXISTS 'writer'; DROP DATABASE IF EXISTS example5")
+					require.NoError(t, err)
+					_, err = db.Exec("CREATE DATABASE example5; CREATE TABLE example5.users (id int PRIMARY KEY, name varchar(100))")
+					require.NoError(t, err)
+				},
+				Config: testAccResourceAlternatorGrantConfig(`"SELECT"`, `"UPDATE"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("alternator_grant.database", "id", "reader@%:example5.*"),
+					resource.TestCheckResourceAttr("alternator_grant.database", "privileges.#", "1"),
+					resource.TestCheckResourceAttr("alternator_grant.column", "id", "grantee@%:example5.users(name)"),
+					resource.TestCheckResourceAttr("alternator_grant.roles", "id", "grantee@%"),
+					resource.TestCheckResourceAttr("alternator_grant.roles", "roles.#", "1"),
+					resource.TestCheckResourceAttr("alternator_grant.other_roles", "roles.#", "1"),
+				),
+			},
+			// Update privileges
+			{
+				Config: testAccResourceAlternatorGrantConfig(`"SELECT", "INSERT"`, `"SELECT", "UPDATE"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("alternator_grant.database", "privileges.#", "2"),
+					resource.TestCheckResourceAttr("alternator_grant.column", "privileges.#", "2"),
+				),
+			},
+			// Revoked outside of Terraform
+			{
+				PreConfig: func() {
+					db, err := sql.Open("mysql", "root@tcp(localhost:23306)/")
+					require.NoError(t, err)
+					_, err = db.Exec("REVOKE INSERT ON example5.* FROM 'reader'")
+					require.NoError(t, err)
+				},
+				Config:             testAccResourceAlternatorGrantConfig(`"SELECT", "INSERT"`, `"SELECT", "UPDATE"`),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			// Import
+			{
+				Config:            testAccResourceAlternatorGrantConfig(`"SELECT", "INSERT"`, `"SELECT", "UPDATE"`),
+				ResourceName:      "alternator_grant.column",
+				ImportState:       true,
+				ImportStateId:     "grantee@%:example5.users(name)",
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccResourceAlternatorGrantConfig(databasePrivileges string, columnPrivileges string) string {
+	return fmt.Sprintf(`
+    %s
+	resource "alternator_user" "grantee" {
+        user     = "grantee"
+        password = "secret"
+	}
+	resource "alternator_role" "reader" {
+        name = "reader"
+	}
+	resource "alternator_grant" "database" {
+        user       = alternator_role.reader.name
+        database   = "example5"
+        privileges = [%s]
+	}
+	resource "alternator_grant" "column" {
+        user       = alternator_user.grantee.user
+        database   = "example5"
+        table      = "users"
+        columns    = ["name"]
+        privileges = [%s]
+	}
+	resource "alternator_grant" "roles" {
+        user  = alternator_user.grantee.user
+        roles = [alternator_role.reader.name]
+	}
+	resource "alternator_role" "writer" {
+        name = "writer"
+	}
+	# Roles granted by another resource are not regarded as drift
+	resource "alternator_grant" "other_roles" {
+        user  = alternator_user.grantee.user
+        roles = [alternator_role.writer.name]
+	}
+	`, provider, databasePrivileges, columnPrivileges)
+}
@@ -0,0 +1,269 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"time"
+)
+
+func resourceAlternatorPartitionRotation() *schema.Resource {
+	return &schema.Resource{
+		Description: "Rotate time-based RANGE partitions of a table. Missing future partitions are added and expired ones are dropped on every apply. " +
+			"Partitions are named like `p20240102` for `day` interval and `p202401` for `month` interval after the start of the period in UTC. " +
+			"Add the table to `ignore_partitions` of `alternator_database_schema` so that it does not revert the rotation.",
+		CreateContext: resourceAlternatorPartitionRotationCreate,
+		ReadContext:   resourceAlternatorPartitionRotationRead,
+		UpdateContext: resourceAlternatorPartitionRotationUpdate,
+		DeleteContext: resourceAlternatorPartitionRotationDelete,
+		Schema: map[string]*schema.Schema{
+			"database": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Target database name.",
+			},
+			"table": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Table partitioned by RANGE or RANGE COLUMNS on the partition column, such as `PARTITION BY RANGE (TO_DAYS(created_at))`.",
+			},
+			"column": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Partition column of date or datetime type.",
+			},
+			"interval": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "day",
+				Description:  "Period covered by a partition. Either `day` or `month`.",
+				ValidateFunc: validation.StringInSlice(partitionIntervalNames(), false),
+			},
+			"premake": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      7,
+				Description:  "Number of future partitions to create in addition to the current one.",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"retention": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				Description:  "Number of past partitions to keep. Older ones are dropped with their rows. `0` means keeping all.",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"partitions": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Names of the current partitions.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"changed": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Used by the provider internal.",
+			},
+			"statements": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Statements to execute on apply.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+		CustomizeDiff: func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+			tflog.Debug(ctx, fmt.Sprintf("@diff start"))
+
+			// cf. resourceAlternatorDatabaseSchema
+			localChanged := d.HasChange("column") || d.HasChange("interval") || d.HasChange("premake") || d.HasChange("retention")
+			remoteChanged := d.Get("changed").(bool)
+			if localChanged || remoteChanged {
+				database := d.Get("database").(string)
+				table := d.Get("table").(string)
+				pp := meta.(*ProviderArguments)
+				if pp.Host == "" {
+					tflog.Debug(ctx, fmt.Sprintf("@diff host is empty. arguments: %+v", pp))
+					return nil
+				}
+
+				client, err := newAlternator(database, pp)
+				if err != nil {
+					tflog.Debug(ctx, fmt.Sprintf("@diff failed to initialize alternator: %s", err.Error()))
+					return nil
+				}
+				defer client.Close()
+
+				partitions, err := fetchTablePartitions(client.Db, database, table)
+				if err != nil {
+					return err
+				}
+				if len(partitions) == 0 {
+					// The table may be created in the same apply
+					tflog.Debug(ctx, fmt.Sprintf("@diff table %s.%s is not partitioned", database, table))
+					err = d.SetNewComputed("partitions")
+					if err != nil {
+						return err
+					}
+					return d.SetNewComputed("statements")
+				}
+				statements, err := partitionRotationStatements(database, table, d.Get("column").(string), partitions,
+					d.Get("interval").(string), d.Get("premake").(int), d.Get("retention").(int), time.Now().UTC())
+				if err != nil {
+					return err
+				}
+				tflog.Debug(ctx, fmt.Sprintf("@diff statements: %s", statements))
+
+				err = d.SetNewComputed("partitions")
+				if err != nil {
+					return err
+				}
+				// statements variable is only for showing diff on planning, and always empty value after applying it.
+				err = d.SetNew("statements", statements)
+				if err != nil {
+					return err
+				}
+			}
+
+			tflog.Debug(ctx, fmt.Sprintf("@diff end"))
+			return nil
+		},
+	}
+}
+
+func resourceAlternatorPartitionRotationCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Debug(ctx, fmt.Sprintf("@create start"))
+
+	database := d.Get("database").(string)
+	table := d.Get("table").(string)
+
+	diags := rotatePartitions(ctx, d, meta, "@create")
+	if diags.HasError() {
+		return diags
+	}
+
+	d.SetId(fmt.Sprintf("%s.%s", database, table))
+
+	tflog.Debug(ctx, fmt.Sprintf("@create end"))
+	return resourceAlternatorPartitionRotationRead(ctx, d, meta)
+}
+
+func resourceAlternatorPartitionRotationRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Debug(ctx, fmt.Sprintf("@read start"))
+
+	database := d.Get("database").(string)
+	table := d.Get("table").(string)
+	pp := meta.(*ProviderArguments)
+	// cf. resourceAlternatorDatabaseSchemaRead
+	if pp.Host == "" {
+		tflog.Debug(ctx, fmt.Sprintf("@read host is empty. arguments: %+v", pp))
+		d.SetId("")
+		return nil
+	}
+
+	client, err := newAlternator(database, pp)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer client.Close()
+
+	partitions, err := fetchTablePartitions(client.Db, database, table)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if len(partitions) == 0 {
+		tflog.Warn(ctx, fmt.Sprintf("@read table %s.%s is not partitioned, removing from state", database, table))
+		d.SetId("")
+		return nil
+	}
+	statements, err := partitionRotationStatements(database, table, d.Get("column").(string), partitions,
+		d.Get("interval").(string), d.Get("premake").(int), d.Get("retention").(int), time.Now().UTC())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	// Partitions are regarded as changed once a new period begins
+	changed := len(statements) > 0
+
+	names := []string{}
+	for _, p := range partitions {
+		names = append(names, p.Name)
+	}
+	tflog.Debug(ctx, fmt.Sprintf("@read partitions: %s", names))
+	tflog.Debug(ctx, fmt.Sprintf("@read changed: %t", changed))
+
+	err = d.Set("partitions", names)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	err = d.Set("changed", changed)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	err = d.Set("statements", []string{})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	tflog.Debug(ctx, fmt.Sprintf("@read end"))
+	return nil
+}
+
+func resourceAlternatorPartitionRotationUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Debug(ctx, fmt.Sprintf("@update start"))
+
+	diags := rotatePartitions(ctx, d, meta, "@update")
+	if diags.HasError() {
+		return diags
+	}
+
+	tflog.Debug(ctx, fmt.Sprintf("@update end"))
+	return resourceAlternatorPartitionRotationRead(ctx, d, meta)
+}
+
+func resourceAlternatorPartitionRotationDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Debug(ctx, fmt.Sprintf("@delete start"))
+
+	// Partitions are left as they are, since dropping them drops the rows
+	d.SetId("")
+
+	tflog.Debug(ctx, fmt.Sprintf("@delete end"))
+	return nil
+}
+
+func rotatePartitions(ctx context.Context, d *schema.ResourceData, meta interface{}, label string) diag.Diagnostics {
+	database := d.Get("database").(string)
+	table := d.Get("table").(string)
+	pp := meta.(*ProviderArguments)
+
+	client, err := newAlternator(database, pp)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer client.Close()
+
+	partitions, err := fetchTablePartitions(client.Db, database, table)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	statements, err := partitionRotationStatements(database, table, d.Get("column").(string), partitions,
+		d.Get("interval").(string), d.Get("premake").(int), d.Get("retention").(int), time.Now().UTC())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	for _, s := range statements {
+		tflog.Info(ctx, fmt.Sprintf("%s executing statements: %s", label, s))
+		_, err := client.Db.Exec(s)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package provider
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestAccResourceAlternatorPartitionRotation(t *testing.T) {
+	today := time.Now().UTC()
+	yesterday := today.AddDate(0, 0, -1)
+	old := today.AddDate(0, 0, -10)
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			// Create
+			{
+				PreConfig: func() {
+					db, err := sql.Open("mysql", "root@tcp(localhost:23306)/?multiStatements=true")
+					require.NoError(t, err)
+					_, err = db.Exec(fmt.Sprintf("DROP DATABASE IF EXISTS example10; CREATE DATABASE example10; "+
+						"CREATE TABLE example10.events (id int, created_at date, PRIMARY KEY (id, created_at)) "+
+						"PARTITION BY RANGE (TO_DAYS(created_at)) ("+
+						"PARTITION p%s VALUES LESS THAN (TO_DAYS('%s')), "+
+						"PARTITION p%s VALUES LESS THAN (TO_DAYS('%s')))",
+						old.Format("20060102"), old.AddDate(0, 0, 1).Format("2006-01-02"),
+						yesterday.Format("20060102"), today.Format("2006-01-02")))
+					require.NoError(t, err)
+				},
+				Config: testAccResourceAlternatorPartitionRotationConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					// yesterday, today and 2 days ahead
+					resource.TestCheckResourceAttr("alternator_partition_rotation.main", "partitions.#", "4"),
+					resource.TestCheckResourceAttr("alternator_partition_rotation.main", "partitions.0", "p"+yesterday.Format("20060102")),
+					resource.TestCheckResourceAttr("alternator_partition_rotation.main", "partitions.3", "p"+today.AddDate(0, 0, 2).Format("20060102")),
+				),
+			},
+			// No changes
+			{
+				Config:   testAccResourceAlternatorPartitionRotationConfig(),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func testAccResourceAlternatorPartitionRotationConfig() string {
+	return fmt.Sprintf(`
+    %s
+	resource "alternator_partition_rotation" "main" {
+        database  = "example10"
+        table     = "events"
+        column    = "created_at"
+        premake   = 2
+        retention = 1
+	}
+	`, provider)
+}
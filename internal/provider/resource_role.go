@@ -0,0 +1,120 @@
+package provider
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceAlternatorRole() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Manage a role of SQL database. Grant privileges to the role by `alternator_grant` with `user` set to the role name.",
+		CreateContext: resourceAlternatorRoleCreate,
+		ReadContext:   resourceAlternatorRoleRead,
+		DeleteContext: resourceAlternatorRoleDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Role name.",
+			},
+		},
+	}
+}
+
+func resourceAlternatorRoleCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Debug(ctx, fmt.Sprintf("@create start"))
+
+	name := d.Get("name").(string)
+	pp := meta.(*ProviderArguments)
+
+	client, err := newAlternator("", pp)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer client.Close()
+
+	statement := fmt.Sprintf("CREATE ROLE %s", quoteAccount(name, "%"))
+	tflog.Info(ctx, fmt.Sprintf("@create executing statements: %s", statement))
+	_, err = client.Db.Exec(statement)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(name)
+
+	tflog.Debug(ctx, fmt.Sprintf("@create end"))
+	return resourceAlternatorRoleRead(ctx, d, meta)
+}
+
+func resourceAlternatorRoleRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Debug(ctx, fmt.Sprintf("@read start"))
+
+	name := d.Id()
+	pp := meta.(*ProviderArguments)
+	// cf. resourceAlternatorDatabaseSchemaRead
+	if pp.Host == "" {
+		tflog.Debug(ctx, fmt.Sprintf("@read host is empty. arguments: %+v", pp))
+		d.SetId("")
+		return nil
+	}
+
+	client, err := newAlternator("", pp)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer client.Close()
+
+	// Roles are stored as accounts in mysql.user
+	var user string
+	err = client.Db.QueryRow("SELECT User FROM mysql.user WHERE User = ? AND Host = '%'", name).Scan(&user)
+	if errors.Is(err, sql.ErrNoRows) {
+		tflog.Warn(ctx, fmt.Sprintf("@read role %s not found, removing from state", name))
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = d.Set("name", name)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	tflog.Debug(ctx, fmt.Sprintf("@read end"))
+	return nil
+}
+
+func resourceAlternatorRoleDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Debug(ctx, fmt.Sprintf("@delete start"))
+
+	name := d.Get("name").(string)
+	pp := meta.(*ProviderArguments)
+
+	client, err := newAlternator("", pp)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer client.Close()
+
+	statement := fmt.Sprintf("DROP ROLE IF EXISTS %s", quoteAccount(name, "%"))
+	tflog.Info(ctx, fmt.Sprintf("@delete executing statements: %s", statement))
+	_, err = client.Db.Exec(statement)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+
+	tflog.Debug(ctx, fmt.Sprintf("@delete end"))
+	return nil
+}
@@ -0,0 +1,333 @@
+package provider
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Variable names are validated since they cannot be quoted in SET statements
+var variableNamePattern = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+func resourceAlternatorServerVariables() *schema.Resource {
+	return &schema.Resource{
+		Description: "Manage global server variables of SQL database. " +
+			"The variables are set back to the values before creation on destroy.",
+		CreateContext: resourceAlternatorServerVariablesCreate,
+		ReadContext:   resourceAlternatorServerVariablesRead,
+		UpdateContext: resourceAlternatorServerVariablesUpdate,
+		DeleteContext: resourceAlternatorServerVariablesDelete,
+		Schema: map[string]*schema.Schema{
+			"variables": {
+				Type:        schema.TypeMap,
+				Required:    true,
+				Description: "Variable names and values. Names must be in lowercase, and values must be written as shown by `SHOW GLOBAL VARIABLES`, such as `ON` instead of `1`.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				ValidateDiagFunc: validation.MapKeyMatch(variableNamePattern, "variable name must consist of lowercase letters, digits and underscores"),
+			},
+			"persist": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Whether to set the variables by `SET PERSIST` so that they survive server restarts. If false, `SET GLOBAL` is used.",
+			},
+			"original_values": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Description: "Values of the variables before they were managed by this resource, restored on destroy.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+func resourceAlternatorServerVariablesCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Debug(ctx, fmt.Sprintf("@create start"))
+
+	variables := expandStringMap(d.Get("variables"))
+	persist := d.Get("persist").(bool)
+	pp := meta.(*ProviderArguments)
+
+	client, err := newAlternator("", pp)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer client.Close()
+
+	originals, err := fetchGlobalVariables(client.Db, mapKeys(variables))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	for _, name := range mapKeys(variables) {
+		if _, ok := originals[name]; !ok {
+			return diag.Errorf("unknown variable %s", name)
+		}
+	}
+	err = d.Set("original_values", originals)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	for _, s := range setVariableStatements(variables, persist) {
+		tflog.Info(ctx, fmt.Sprintf("@create executing statements: %s", s))
+		_, err := client.Db.Exec(s)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	d.SetId(serverVariablesId(pp.Host, variables))
+
+	tflog.Debug(ctx, fmt.Sprintf("@create end"))
+	return resourceAlternatorServerVariablesRead(ctx, d, meta)
+}
+
+func resourceAlternatorServerVariablesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Debug(ctx, fmt.Sprintf("@read start"))
+
+	variables := expandStringMap(d.Get("variables"))
+	persist := d.Get("persist").(bool)
+	pp := meta.(*ProviderArguments)
+	// cf. resourceAlternatorDatabaseSchemaRead
+	if pp.Host == "" {
+		tflog.Debug(ctx, fmt.Sprintf("@read host is empty. arguments: %+v", pp))
+		d.SetId("")
+		return nil
+	}
+
+	client, err := newAlternator("", pp)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer client.Close()
+
+	var values map[string]string
+	if persist {
+		// Variables not persisted are regarded as drift
+		values, err = fetchPersistedVariables(client.Db, mapKeys(variables))
+	} else {
+		values, err = fetchGlobalVariables(client.Db, mapKeys(variables))
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	tflog.Debug(ctx, fmt.Sprintf("@read variables: %+v", values))
+
+	err = d.Set("variables", values)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	tflog.Debug(ctx, fmt.Sprintf("@read end"))
+	return nil
+}
+
+func resourceAlternatorServerVariablesUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Debug(ctx, fmt.Sprintf("@update start"))
+
+	o, n := d.GetChange("variables")
+	oldVariables := expandStringMap(o)
+	newVariables := expandStringMap(n)
+	persist := d.Get("persist").(bool)
+	originals := expandStringMap(d.Get("original_values"))
+	pp := meta.(*ProviderArguments)
+
+	client, err := newAlternator("", pp)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer client.Close()
+
+	// Remember original values of newly managed variables
+	added := []string{}
+	for _, name := range mapKeys(newVariables) {
+		if _, ok := originals[name]; !ok {
+			added = append(added, name)
+		}
+	}
+	addedOriginals, err := fetchGlobalVariables(client.Db, added)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	for _, name := range added {
+		v, ok := addedOriginals[name]
+		if !ok {
+			return diag.Errorf("unknown variable %s", name)
+		}
+		originals[name] = v
+	}
+
+	// Restore variables no longer managed
+	restored := map[string]string{}
+	for _, name := range mapKeys(oldVariables) {
+		if _, ok := newVariables[name]; !ok {
+			restored[name] = originals[name]
+			delete(originals, name)
+		}
+	}
+	// The variables were persisted only if the resource persisted them before the change
+	oldPersist, _ := d.GetChange("persist")
+	statements := restoreVariableStatements(restored, oldPersist.(bool))
+
+	changed := map[string]string{}
+	for name, v := range newVariables {
+		if ov, ok := oldVariables[name]; !ok || ov != v || d.HasChange("persist") {
+			changed[name] = v
+		}
+	}
+	// Variables persisted before are no longer persisted
+	if d.HasChange("persist") && !persist {
+		for _, name := range mapKeys(changed) {
+			statements = append(statements, fmt.Sprintf("RESET PERSIST IF EXISTS %s", name))
+		}
+	}
+	statements = append(statements, setVariableStatements(changed, persist)...)
+
+	err = d.Set("original_values", originals)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	for _, s := range statements {
+		tflog.Info(ctx, fmt.Sprintf("@update executing statements: %s", s))
+		_, err := client.Db.Exec(s)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	d.SetId(serverVariablesId(pp.Host, newVariables))
+
+	tflog.Debug(ctx, fmt.Sprintf("@update end"))
+	return resourceAlternatorServerVariablesRead(ctx, d, meta)
+}
+
+func resourceAlternatorServerVariablesDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Debug(ctx, fmt.Sprintf("@delete start"))
+
+	originals := expandStringMap(d.Get("original_values"))
+	persist := d.Get("persist").(bool)
+	pp := meta.(*ProviderArguments)
+
+	client, err := newAlternator("", pp)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer client.Close()
+
+	for _, s := range restoreVariableStatements(originals, persist) {
+		tflog.Info(ctx, fmt.Sprintf("@delete executing statements: %s", s))
+		_, err := client.Db.Exec(s)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	d.SetId("")
+
+	tflog.Debug(ctx, fmt.Sprintf("@delete end"))
+	return nil
+}
+
+// serverVariablesId returns the ID unique to the host and the managed variables,
+// so that multiple resources can manage different variables of the same host.
+func serverVariablesId(host string, variables map[string]string) string {
+	return fmt.Sprintf("%s/%s", host, strings.Join(mapKeys(variables), ","))
+}
+
+// fetchGlobalVariables uses SHOW GLOBAL VARIABLES, which is available regardless of the flavor and performance_schema.
+func fetchGlobalVariables(db *sql.DB, names []string) (map[string]string, error) {
+	return fetchVariables(db, "global variables", "SHOW GLOBAL VARIABLES WHERE Variable_name IN (%s)", names)
+}
+
+func fetchPersistedVariables(db *sql.DB, names []string) (map[string]string, error) {
+	return fetchVariables(db, "persisted variables", "SELECT VARIABLE_NAME, VARIABLE_VALUE FROM performance_schema.persisted_variables WHERE VARIABLE_NAME IN (%s)", names)
+}
+
+// fetchVariables returns the values keyed by the lowercase names.
+func fetchVariables(db *sql.DB, target string, query string, names []string) (map[string]string, error) {
+	ret := map[string]string{}
+	if len(names) == 0 {
+		return ret, nil
+	}
+	placeholders := []string{}
+	args := []interface{}{}
+	for _, n := range names {
+		placeholders = append(placeholders, "?")
+		args = append(args, strings.ToLower(n))
+	}
+	rows, err := db.Query(fmt.Sprintf(query, strings.Join(placeholders, ", ")), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s : %w", target, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name string
+		var value sql.NullString
+		err := rows.Scan(&name, &value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query %s : %w", target, err)
+		}
+		ret[strings.ToLower(name)] = value.String
+	}
+	return ret, rows.Err()
+}
+
+func setVariableStatements(variables map[string]string, persist bool) []string {
+	scope := "GLOBAL"
+	if persist {
+		scope = "PERSIST"
+	}
+	ret := []string{}
+	for _, name := range mapKeys(variables) {
+		ret = append(ret, fmt.Sprintf("SET %s %s = %s", scope, name, variableValue(variables[name])))
+	}
+	return ret
+}
+
+// restoreVariableStatements returns statements to set the variables back to the given values, removing them from persisted variables if persist is true.
+func restoreVariableStatements(variables map[string]string, persist bool) []string {
+	ret := []string{}
+	for _, name := range mapKeys(variables) {
+		if persist {
+			ret = append(ret, fmt.Sprintf("RESET PERSIST IF EXISTS %s", name))
+		}
+	}
+	return append(ret, setVariableStatements(variables, false)...)
+}
+
+// variableValue formats the value of SET statement. Numeric values must not be quoted.
+func variableValue(v string) string {
+	if _, err := strconv.ParseFloat(v, 64); err == nil {
+		return v
+	}
+	return quoteString(v)
+}
+
+func expandStringMap(v interface{}) map[string]string {
+	ret := map[string]string{}
+	for k, e := range v.(map[string]interface{}) {
+		ret[k] = e.(string)
+	}
+	return ret
+}
+
+func mapKeys(m map[string]string) []string {
+	ret := []string{}
+	for k := range m {
+		ret = append(ret, k)
+	}
+	sort.Strings(ret)
+	return ret
+}
@@ -0,0 +1,106 @@
+package provider
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestAccResourceAlternatorServerVariables(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckGlobalVariable("sql_require_primary_key", "OFF"),
+		Steps: []resource.TestStep{
+			// Create
+			{
+				PreConfig: func() {
+					db, err := sql.Open("mysql", "root@tcp(localhost:23306)/?multiStatements=true")
+					require.NoError(t, err)
+					_, err = db.Exec("RESET PERSIST IF EXISTS sql_require_primary_key; SET GLOBAL sql_require_primary_key = OFF")
+					require.NoError(t, err)
+				},
+				Config: testAccResourceAlternatorServerVariablesConfig("ON"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("alternator_server_variables.main", "id", "localhost:23306/sql_require_primary_key"),
+					resource.TestCheckResourceAttr("alternator_server_variables.main", "variables.sql_require_primary_key", "ON"),
+					resource.TestCheckResourceAttr("alternator_server_variables.main", "original_values.sql_require_primary_key", "OFF"),
+					testAccCheckGlobalVariable("sql_require_primary_key", "ON"),
+				),
+			},
+			// Changed outside of Terraform
+			{
+				PreConfig: func() {
+					db, err := sql.Open("mysql", "root@tcp(localhost:23306)/")
+					require.NoError(t, err)
+					_, err = db.Exec("SET PERSIST sql_require_primary_key = OFF")
+					require.NoError(t, err)
+				},
+				Config:             testAccResourceAlternatorServerVariablesConfig("ON"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			// Revert the change
+			{
+				Config: testAccResourceAlternatorServerVariablesConfig("ON"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGlobalVariable("sql_require_primary_key", "ON"),
+				),
+			},
+		},
+	})
+}
+
+func TestVariableValue(t *testing.T) {
+	require.Equal(t, "1024", variableValue("1024"))
+	require.Equal(t, "'ON'", variableValue("ON"))
+	require.Equal(t, "'utf8mb4'", variableValue("utf8mb4"))
+}
+
+func TestRestoreVariableStatements(t *testing.T) {
+	variables := map[string]string{"max_connections": "151"}
+	require.Equal(t, []string{"SET GLOBAL max_connections = 151"}, restoreVariableStatements(variables, false))
+	require.Equal(t, []string{"RESET PERSIST IF EXISTS max_connections", "SET GLOBAL max_connections = 151"}, restoreVariableStatements(variables, true))
+}
+
+func TestServerVariablesId(t *testing.T) {
+	require.Equal(t, "localhost:3306/max_connections,sql_require_primary_key",
+		serverVariablesId("localhost:3306", map[string]string{"sql_require_primary_key": "ON", "max_connections": "100"}))
+}
+
+func TestVariableNamePattern(t *testing.T) {
+	require.True(t, variableNamePattern.MatchString("sql_require_primary_key"))
+	require.False(t, variableNamePattern.MatchString("MAX_CONNECTIONS"))
+	require.False(t, variableNamePattern.MatchString("max_connections = 1; DROP USER root"))
+}
+
+func testAccCheckGlobalVariable(name string, expected string) resource.TestCheckFunc {
+	return func(state *terraform.State) error {
+		db, err := sql.Open("mysql", "root@tcp(localhost:23306)/")
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+		values, err := fetchGlobalVariables(db, []string{name})
+		if err != nil {
+			return err
+		}
+		if values[name] != expected {
+			return fmt.Errorf("variable %s is expected to be %s, but got %s", name, expected, values[name])
+		}
+		return nil
+	}
+}
+
+func testAccResourceAlternatorServerVariablesConfig(value string) string {
+	return fmt.Sprintf(`
+    %s
+	resource "alternator_server_variables" "main" {
+        variables = {
+            sql_require_primary_key = "%s"
+        }
+	}
+	`, provider, value)
+}
@@ -0,0 +1,302 @@
+package provider
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/id"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"reflect"
+)
+
+func resourceAlternatorSql() *schema.Resource {
+	return &schema.Resource{
+		Description: "Execute arbitrary SQL statements which cannot be expressed by the other resources. " +
+			"The statements should be idempotent, because they may be executed again when `triggers` are changed or drift is detected by `read_query`.",
+		CreateContext: resourceAlternatorSqlCreate,
+		ReadContext:   resourceAlternatorSqlRead,
+		UpdateContext: resourceAlternatorSqlUpdate,
+		DeleteContext: resourceAlternatorSqlDelete,
+		Schema: map[string]*schema.Schema{
+			"database": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Default database of the statements. Unqualified names in the statements are resolved in it.",
+			},
+			"create_sql": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Statements to execute on creation. Multiple statements can be separated by `;`, and `DELIMITER` command is supported.",
+			},
+			"update_sql": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Statements to execute when `create_sql`, `update_sql` or `triggers` is changed, or the result of `read_query` is changed. `create_sql` is executed instead if not specified.",
+			},
+			"destroy_sql": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Statements to execute on destruction.",
+			},
+			"read_query": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Description: "Query to detect drift. If it returns no rows, the statements are regarded as reverted outside of Terraform and `create_sql` is planned to be executed again. " +
+					"If it returns rows different from the ones after the last apply, `update_sql` is planned to be executed again. " +
+					"Changing it only refreshes `read_result` without executing the statements.",
+			},
+			"triggers": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Description: "Arbitrary values that cause the statements to be executed again when changed.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"read_result": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Rows returned by `read_query` after the last apply. Each row is a map from column names to values, in which NULL columns are omitted.",
+				Elem: &schema.Schema{
+					Type: schema.TypeMap,
+					Elem: &schema.Schema{
+						Type: schema.TypeString,
+					},
+				},
+			},
+			"changed": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Used by the provider internal.",
+			},
+		},
+		CustomizeDiff: func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+			tflog.Debug(ctx, fmt.Sprintf("@diff start"))
+
+			// cf. resourceAlternatorDatabaseSchema
+			localChanged := d.HasChange("create_sql") || d.HasChange("update_sql") || d.HasChange("triggers")
+			remoteChanged := d.Get("changed").(bool)
+			// The result of the new read_query becomes the new baseline
+			queryChanged := d.HasChange("read_query")
+			if d.Id() != "" && (localChanged || remoteChanged || queryChanged) {
+				err := d.SetNewComputed("read_result")
+				if err != nil {
+					return err
+				}
+			}
+
+			tflog.Debug(ctx, fmt.Sprintf("@diff end"))
+			return nil
+		},
+	}
+}
+
+func resourceAlternatorSqlCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Debug(ctx, fmt.Sprintf("@create start"))
+
+	database := d.Get("database").(string)
+	pp := meta.(*ProviderArguments)
+
+	client, err := newAlternator(database, pp)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer client.Close()
+
+	err = execStatements(ctx, client.Db, database, splitStatements(d.Get("create_sql").(string)), "@create")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(id.UniqueId())
+
+	tflog.Debug(ctx, fmt.Sprintf("@create end"))
+	return readSqlResult(ctx, d, meta, "@create", true)
+}
+
+func resourceAlternatorSqlRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Debug(ctx, fmt.Sprintf("@read start"))
+
+	diags := readSqlResult(ctx, d, meta, "@read", false)
+
+	tflog.Debug(ctx, fmt.Sprintf("@read end"))
+	return diags
+}
+
+func resourceAlternatorSqlUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Debug(ctx, fmt.Sprintf("@update start"))
+
+	database := d.Get("database").(string)
+	pp := meta.(*ProviderArguments)
+
+	// Executed again if the result of read_query has been changed, unless read_query itself has been changed,
+	// since the result of the old query cannot be compared with the one of the new query
+	remoteChanged := d.Get("changed").(bool) && !d.HasChange("read_query")
+	if d.HasChanges("create_sql", "update_sql", "triggers") || remoteChanged {
+		client, err := newAlternator(database, pp)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		defer client.Close()
+
+		sqlStr := d.Get("update_sql").(string)
+		if sqlStr == "" {
+			sqlStr = d.Get("create_sql").(string)
+		}
+		err = execStatements(ctx, client.Db, database, splitStatements(sqlStr), "@update")
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	tflog.Debug(ctx, fmt.Sprintf("@update end"))
+	return readSqlResult(ctx, d, meta, "@update", true)
+}
+
+func resourceAlternatorSqlDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Debug(ctx, fmt.Sprintf("@delete start"))
+
+	database := d.Get("database").(string)
+	destroySql := d.Get("destroy_sql").(string)
+	pp := meta.(*ProviderArguments)
+
+	if destroySql != "" {
+		client, err := newAlternator(database, pp)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		defer client.Close()
+
+		err = execStatements(ctx, client.Db, database, splitStatements(destroySql), "@delete")
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	d.SetId("")
+
+	tflog.Debug(ctx, fmt.Sprintf("@delete end"))
+	return nil
+}
+
+// readSqlResult executes read_query and stores the result.
+// If baseline is true, the result is regarded as the expected one, which has just been applied.
+// Otherwise, the result is compared with the stored one, and the difference is regarded as drift.
+func readSqlResult(ctx context.Context, d *schema.ResourceData, meta interface{}, label string, baseline bool) diag.Diagnostics {
+	database := d.Get("database").(string)
+	readQuery := d.Get("read_query").(string)
+	pp := meta.(*ProviderArguments)
+	// cf. resourceAlternatorDatabaseSchemaRead
+	if pp.Host == "" {
+		tflog.Debug(ctx, fmt.Sprintf("%s host is empty. arguments: %+v", label, pp))
+		d.SetId("")
+		return nil
+	}
+
+	rows := []map[string]string{}
+	if readQuery != "" {
+		client, err := newAlternator(database, pp)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		defer client.Close()
+
+		rows, err = queryRows(ctx, client.Db, database, readQuery)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if len(rows) == 0 && !baseline {
+			tflog.Warn(ctx, fmt.Sprintf("%s read_query returned no rows, removing from state", label))
+			d.SetId("")
+			return nil
+		}
+	}
+
+	result := flattenRows(rows)
+	changed := !baseline && !reflect.DeepEqual(d.Get("read_result"), result)
+	tflog.Debug(ctx, fmt.Sprintf("%s changed: %t", label, changed))
+	err := d.Set("changed", changed)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	// The stored result is kept as the expected one until the drift is reverted
+	if changed {
+		return nil
+	}
+	err = d.Set("read_result", result)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+// queryRows executes the query in the database and returns the rows as maps from column names to values.
+// NULL columns are omitted from the maps.
+func queryRows(ctx context.Context, db *sql.DB, database string, query string) ([]map[string]string, error) {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if database != "" {
+		_, err = conn.ExecContext(ctx, fmt.Sprintf("USE `%s`", database))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	rows, err := conn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query : %w", err)
+	}
+	defer rows.Close()
+	return scanRows(rows, 0)
+}
+
+// scanRows returns the rows as maps from column names to values. NULL columns are omitted from the maps.
+// It returns an error if there are more rows than limit. limit 0 means no limit.
+func scanRows(rows *sql.Rows, limit int) ([]map[string]string, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	ret := []map[string]string{}
+	for rows.Next() {
+		if limit > 0 && len(ret) == limit {
+			return nil, fmt.Errorf("query returned more than %d rows", limit)
+		}
+		values := make([]sql.NullString, len(columns))
+		dest := []interface{}{}
+		for i := range values {
+			dest = append(dest, &values[i])
+		}
+		err := rows.Scan(dest...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row : %w", err)
+		}
+		row := map[string]string{}
+		for i, c := range columns {
+			if values[i].Valid {
+				row[c] = values[i].String
+			}
+		}
+		ret = append(ret, row)
+	}
+	return ret, rows.Err()
+}
+
+func flattenRows(rows []map[string]string) []interface{} {
+	ret := []interface{}{}
+	for _, r := range rows {
+		m := map[string]interface{}{}
+		for k, v := range r {
+			m[k] = v
+		}
+		ret = append(ret, m)
+	}
+	return ret
+}
@@ -0,0 +1,107 @@
+package provider
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestAccResourceAlternatorSql(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			// Create
+			{
+				PreConfig: func() {
+					db, err := sql.Open("mysql", "root@tcp(localhost:23306)/?multiStatements=true")
+					require.NoError(t, err)
+					_, err = db.Exec("DROP DATABASE IF EXISTS example6; CREATE DATABASE example6; CREATE TABLE example6.flags (name varchar(100) PRIMARY KEY, enabled bool)")
+					require.NoError(t, err)
+				},
+				Config: testAccResourceAlternatorSqlConfig("1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("alternator_sql.main", "read_result.#", "1"),
+					resource.TestCheckResourceAttr("alternator_sql.main", "read_result.0.enabled", "1"),
+				),
+			},
+			// Update triggers
+			{
+				Config: testAccResourceAlternatorSqlConfig("2"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("alternator_sql.main", "read_result.0.enabled", "0"),
+				),
+			},
+			// Changed outside of Terraform
+			{
+				PreConfig: func() {
+					db, err := sql.Open("mysql", "root@tcp(localhost:23306)/")
+					require.NoError(t, err)
+					_, err = db.Exec("UPDATE example6.flags SET enabled = true WHERE name = 'new_feature'")
+					require.NoError(t, err)
+				},
+				Config:             testAccResourceAlternatorSqlConfig("2"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			// Apply update_sql again
+			{
+				Config: testAccResourceAlternatorSqlConfig("2"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("alternator_sql.main", "read_result.0.enabled", "0"),
+					resource.TestCheckResourceAttr("alternator_sql.main", "changed", "false"),
+				),
+			},
+			// Changing read_query only refreshes read_result, without executing update_sql
+			{
+				PreConfig: func() {
+					db, err := sql.Open("mysql", "root@tcp(localhost:23306)/?multiStatements=true")
+					require.NoError(t, err)
+					_, err = db.Exec("UPDATE example6.flags SET enabled = true WHERE name = 'new_feature'; " +
+						"INSERT INTO example6.flags (name, enabled) VALUES ('other_feature', true)")
+					require.NoError(t, err)
+				},
+				Config: testAccResourceAlternatorSqlQueryConfig("2", "SELECT name, enabled FROM flags ORDER BY name"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("alternator_sql.main", "read_result.#", "2"),
+					resource.TestCheckResourceAttr("alternator_sql.main", "read_result.0.enabled", "1"),
+					resource.TestCheckResourceAttr("alternator_sql.main", "read_result.1.enabled", "1"),
+					resource.TestCheckResourceAttr("alternator_sql.main", "changed", "false"),
+				),
+			},
+			// Deleted outside of Terraform
+			{
+				PreConfig: func() {
+					db, err := sql.Open("mysql", "root@tcp(localhost:23306)/")
+					require.NoError(t, err)
+					_, err = db.Exec("DELETE FROM example6.flags")
+					require.NoError(t, err)
+				},
+				Config:             testAccResourceAlternatorSqlConfig("2"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func testAccResourceAlternatorSqlConfig(version string) string {
+	return testAccResourceAlternatorSqlQueryConfig(version, "SELECT name, enabled FROM flags WHERE name = 'new_feature'")
+}
+
+func testAccResourceAlternatorSqlQueryConfig(version string, readQuery string) string {
+	return fmt.Sprintf(`
+    %s
+	resource "alternator_sql" "main" {
+        database    = "example6"
+        create_sql  = "INSERT IGNORE INTO flags (name, enabled) VALUES ('new_feature', true)"
+        update_sql  = "UPDATE flags SET enabled = false WHERE name = 'new_feature'"
+        destroy_sql = "DELETE FROM flags WHERE name = 'new_feature'"
+        read_query  = "%s"
+        triggers = {
+            version = "%s"
+        }
+	}
+	`, provider, readQuery, version)
+}
@@ -0,0 +1,377 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/kota65535/alternator/cmd"
+	"github.com/kota65535/alternator/lib"
+	"github.com/kota65535/alternator/parser"
+	"strings"
+)
+
+var errDatabaseNotFound = errors.New("database not found")
+
+func resourceAlternatorTable() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Manage a single table of SQL database by [Alternator](https://github.com/kota65535/alternator). Other tables in the database are left untouched.",
+		CreateContext: resourceAlternatorTableCreate,
+		ReadContext:   resourceAlternatorTableRead,
+		UpdateContext: resourceAlternatorTableUpdate,
+		DeleteContext: resourceAlternatorTableDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceAlternatorTableImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"database": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Target database name. The database must already exist.",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Table name. Changing it renames the table.",
+			},
+			"definition": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Table definition, composed by a single `CREATE TABLE` statement.",
+			},
+			"remote_definition": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Actual remote table definition.",
+			},
+			"changed": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Used by the provider internal.",
+			},
+			"statements": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Statements to execute on apply.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+		CustomizeDiff: func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+			tflog.Debug(ctx, fmt.Sprintf("@diff start"))
+
+			localChanged := d.HasChange("definition") || d.HasChange("name")
+			// cf. resourceAlternatorDatabaseSchema
+			remoteChanged := d.Get("changed").(bool)
+			if localChanged || remoteChanged {
+				database := d.Get("database").(string)
+				oldName, name := d.GetChange("name")
+				definition := d.Get("definition").(string)
+				pp := meta.(*ProviderArguments)
+				if pp.Host == "" {
+					tflog.Debug(ctx, fmt.Sprintf("@diff host is empty. arguments: %+v", pp))
+					return nil
+				}
+
+				client, err := newAlternator(database, pp)
+				if err != nil {
+					tflog.Debug(ctx, fmt.Sprintf("@diff failed to initialize alternator: %s", err.Error()))
+					return nil
+				}
+				defer client.Close()
+
+				if oldName.(string) == "" {
+					oldName = name
+				}
+				alt, _, localTable, err := getTableAlterations(client, database, oldName.(string), name.(string), definition)
+				if errors.Is(err, errDatabaseNotFound) {
+					// The database may be created in the same apply
+					tflog.Debug(ctx, fmt.Sprintf("@diff database %s not found", database))
+					err = d.SetNewComputed("remote_definition")
+					if err != nil {
+						return err
+					}
+					return d.SetNewComputed("statements")
+				}
+				if err != nil {
+					return err
+				}
+				statements := tableStatements(database, oldName.(string), name.(string), alt)
+				tflog.Debug(ctx, fmt.Sprintf("@diff statements: %s", statements))
+
+				err = d.SetNew("remote_definition", tableDefinitionString(localTable))
+				if err != nil {
+					return err
+				}
+				// statements variable is only for showing diff on planning, and always empty value after applying it.
+				err = d.SetNew("statements", statements)
+				if err != nil {
+					return err
+				}
+			}
+
+			tflog.Debug(ctx, fmt.Sprintf("@diff end"))
+			return nil
+		},
+	}
+}
+
+func resourceAlternatorTableCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Debug(ctx, fmt.Sprintf("@create start"))
+
+	database := d.Get("database").(string)
+	name := d.Get("name").(string)
+	definition := d.Get("definition").(string)
+	pp := meta.(*ProviderArguments)
+
+	client, err := newAlternator(database, pp)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer client.Close()
+
+	alt, remoteTable, _, err := getTableAlterations(client, database, name, name, definition)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if remoteTable != nil {
+		return diag.Errorf("table %s.%s already exists. import it to manage by Terraform", database, name)
+	}
+	for _, s := range alt.Statements() {
+		tflog.Info(ctx, fmt.Sprintf("@create executing statements: %s", s))
+		_, err := client.Db.Exec(s)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	d.SetId(fmt.Sprintf("%s.%s", database, name))
+
+	tflog.Debug(ctx, fmt.Sprintf("@create end"))
+	return resourceAlternatorTableRead(ctx, d, meta)
+}
+
+func resourceAlternatorTableRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Debug(ctx, fmt.Sprintf("@read start"))
+
+	database := d.Get("database").(string)
+	name := d.Get("name").(string)
+	definition := d.Get("definition").(string)
+	pp := meta.(*ProviderArguments)
+	// cf. resourceAlternatorDatabaseSchemaRead
+	if pp.Host == "" {
+		tflog.Debug(ctx, fmt.Sprintf("@read host is empty. arguments: %+v", pp))
+		d.SetId("")
+		return nil
+	}
+
+	client, err := newAlternator(database, pp)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer client.Close()
+
+	alt, remoteTable, _, err := getTableAlterations(client, database, name, name, definition)
+	if errors.Is(err, errDatabaseNotFound) {
+		tflog.Warn(ctx, fmt.Sprintf("@read database %s not found, removing from state", database))
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if remoteTable == nil {
+		tflog.Warn(ctx, fmt.Sprintf("@read table %s.%s not found, removing from state", database, name))
+		d.SetId("")
+		return nil
+	}
+
+	remoteDefinition := tableDefinitionString(remoteTable)
+	changed := len(alt.Statements()) > 0
+
+	tflog.Debug(ctx, fmt.Sprintf("@read remote_definition: %s", remoteDefinition))
+	tflog.Debug(ctx, fmt.Sprintf("@read changed: %t", changed))
+
+	err = d.Set("remote_definition", remoteDefinition)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	err = d.Set("changed", changed)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	err = d.Set("statements", []string{})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(fmt.Sprintf("%s.%s", database, name))
+
+	tflog.Debug(ctx, fmt.Sprintf("@read end"))
+	return nil
+}
+
+func resourceAlternatorTableUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Debug(ctx, fmt.Sprintf("@update start"))
+
+	database := d.Get("database").(string)
+	oldName, name := d.GetChange("name")
+	definition := d.Get("definition").(string)
+	pp := meta.(*ProviderArguments)
+
+	client, err := newAlternator(database, pp)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer client.Close()
+
+	alt, _, _, err := getTableAlterations(client, database, oldName.(string), name.(string), definition)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	for _, s := range tableStatements(database, oldName.(string), name.(string), alt) {
+		tflog.Info(ctx, fmt.Sprintf("@update executing statements: %s", s))
+		_, err := client.Db.Exec(s)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	d.SetId(fmt.Sprintf("%s.%s", database, name))
+
+	tflog.Debug(ctx, fmt.Sprintf("@update end"))
+	return resourceAlternatorTableRead(ctx, d, meta)
+}
+
+func resourceAlternatorTableDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Debug(ctx, fmt.Sprintf("@delete start"))
+
+	database := d.Get("database").(string)
+	name := d.Get("name").(string)
+	pp := meta.(*ProviderArguments)
+
+	client, err := newAlternator(database, pp)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer client.Close()
+
+	// Alternator cannot calculate the table drop alone if it has foreign keys referencing other tables,
+	// so we execute DROP TABLE statement directly.
+	s := fmt.Sprintf("DROP TABLE IF EXISTS `%s`.`%s`", database, name)
+	tflog.Info(ctx, fmt.Sprintf("@delete executing statements: %s", s))
+	_, err = client.Db.Exec(s)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+
+	tflog.Debug(ctx, fmt.Sprintf("@delete end"))
+	return nil
+}
+
+func resourceAlternatorTableImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	id := d.Id()
+	tflog.Debug(ctx, fmt.Sprintf("@import id = %s", id))
+
+	database, name, ok := strings.Cut(id, ".")
+	if !ok || database == "" || name == "" {
+		return nil, fmt.Errorf("import ID must be in the form of \"database.table\": %s", id)
+	}
+	pp := meta.(*ProviderArguments)
+
+	client, err := newAlternator(database, pp)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	_, remoteTable, _, err := getTableAlterations(client, database, name, name, "")
+	if err != nil {
+		return nil, err
+	}
+	if remoteTable == nil {
+		return nil, fmt.Errorf("table %s.%s does not exist", database, name)
+	}
+
+	err = d.Set("database", database)
+	if err != nil {
+		return nil, err
+	}
+	err = d.Set("name", name)
+	if err != nil {
+		return nil, err
+	}
+	err = d.Set("definition", tableDefinitionString(remoteTable))
+	if err != nil {
+		return nil, err
+	}
+	d.SetId(fmt.Sprintf("%s.%s", database, name))
+	return []*schema.ResourceData{d}, nil
+}
+
+// getTableAlterations calculates alterations of a single table.
+// The remote table is looked up by remoteName and compared as if it has already been renamed to name.
+// If definition is empty, the local table is regarded as the same as the remote one.
+func getTableAlterations(client *cmd.Alternator, database string, remoteName string, name string, definition string) (*lib.TableAlterations, *parser.CreateTableStatement, *parser.CreateTableStatement, error) {
+	remoteSchemas, err := client.FetchSchemas()
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to fetch remote schema : %w", err)
+	}
+	if len(remoteSchemas) == 0 {
+		return nil, nil, nil, fmt.Errorf("%w: %s", errDatabaseNotFound, database)
+	}
+	remoteTable := findTable(remoteSchemas[0], remoteName)
+
+	from := []*parser.CreateTableStatement{}
+	if remoteTable != nil {
+		// Compare the renamed copy not to modify the remote table definition
+		renamed := *remoteTable
+		renamed.TableName = name
+		from = append(from, &renamed)
+	}
+	if definition == "" {
+		alt := lib.NewTableAlterations(from, from)
+		return &alt, remoteTable, remoteTable, nil
+	}
+
+	// Use the remote database definition not to change the database options
+	localSchemaStr := fmt.Sprintf("%s\nUSE `%s`;\n%s", remoteSchemas[0].Database.String(), database, definition)
+	localSchemas, err := client.ReadSchemas(localSchemaStr)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to read table definition : %w", err)
+	}
+	localTables := localSchemas[0].Tables
+	if len(localTables) != 1 {
+		return nil, nil, nil, fmt.Errorf("table definition must contain exactly one CREATE TABLE statement, but found %d", len(localTables))
+	}
+	if localTables[0].TableName != name {
+		return nil, nil, nil, fmt.Errorf("table name in the definition %s does not match the name argument %s", localTables[0].TableName, name)
+	}
+
+	alt := lib.NewTableAlterations(from, localTables)
+	return &alt, remoteTable, localTables[0], nil
+}
+
+// tableStatements returns statements to alter the table, including renaming.
+func tableStatements(database string, oldName string, name string, alt *lib.TableAlterations) []string {
+	statements := []string{}
+	if oldName != name {
+		statements = append(statements, fmt.Sprintf("RENAME TABLE `%s`.`%s` TO `%s`.`%s`;", database, oldName, database, name))
+	}
+	return append(statements, alt.Statements()...)
+}
+
+// tableDefinitionString returns CREATE TABLE statement without the database name.
+func tableDefinitionString(t *parser.CreateTableStatement) string {
+	if t == nil {
+		return ""
+	}
+	unqualified := *t
+	unqualified.DbName = ""
+	return unqualified.String()
+}
@@ -0,0 +1,549 @@
+package provider
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"sort"
+	"strings"
+)
+
+func resourceAlternatorTableRows() *schema.Resource {
+	return &schema.Resource{
+		Description: "Manage all rows of a table, such as lookup tables that must be identical across environments. " +
+			"Rows not listed in `rows` are deleted, and changes are applied in a transaction.",
+		CreateContext: resourceAlternatorTableRowsCreate,
+		ReadContext:   resourceAlternatorTableRowsRead,
+		UpdateContext: resourceAlternatorTableRowsUpdate,
+		DeleteContext: resourceAlternatorTableRowsDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceAlternatorTableRowsImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"database": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Target database name.",
+			},
+			"table": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Target table name. The table must already exist.",
+			},
+			"keys": {
+				Type:        schema.TypeList,
+				Required:    true,
+				ForceNew:    true,
+				MinItems:    1,
+				Description: "Columns identifying a row, typically the primary key columns.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"rows": {
+				Type:        schema.TypeList,
+				Required:    true,
+				Description: "Rows of the table. Each row is a map from column names to values, which must contain all the `keys` columns. Columns omitted in some rows are set to NULL, and columns omitted in all rows are left untouched. Values must be written as returned by `SELECT`, such as `1` instead of `true`.",
+				Elem: &schema.Schema{
+					Type: schema.TypeMap,
+					Elem: &schema.Schema{
+						Type: schema.TypeString,
+					},
+				},
+			},
+			"remote_rows": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Actual rows of the table, limited to the columns in `rows`.",
+				Elem: &schema.Schema{
+					Type: schema.TypeMap,
+					Elem: &schema.Schema{
+						Type: schema.TypeString,
+					},
+				},
+			},
+			"changed": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Used by the provider internal.",
+			},
+			"statements": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Statements to execute on apply.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+		CustomizeDiff: func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+			tflog.Debug(ctx, fmt.Sprintf("@diff start"))
+
+			// cf. resourceAlternatorDatabaseSchema
+			localChanged := d.HasChange("rows") || d.HasChange("keys")
+			remoteChanged := d.Get("changed").(bool)
+			if localChanged || remoteChanged {
+				if !d.NewValueKnown("rows") {
+					err := d.SetNewComputed("remote_rows")
+					if err != nil {
+						return err
+					}
+					return d.SetNewComputed("statements")
+				}
+				database := d.Get("database").(string)
+				table := d.Get("table").(string)
+				keys := expandStringList(d.Get("keys"))
+				rows := expandRows(d.Get("rows"))
+				err := validateRowKeys(keys, rows)
+				if err != nil {
+					return err
+				}
+				pp := meta.(*ProviderArguments)
+				if pp.Host == "" {
+					tflog.Debug(ctx, fmt.Sprintf("@diff host is empty. arguments: %+v", pp))
+					return nil
+				}
+
+				client, err := newAlternator(database, pp)
+				if err != nil {
+					tflog.Debug(ctx, fmt.Sprintf("@diff failed to initialize alternator: %s", err.Error()))
+					return nil
+				}
+				defer client.Close()
+
+				remoteRows, err := fetchTableRows(ctx, client.Db, database, table, keys)
+				if err != nil {
+					// The table may be created in the same apply
+					tflog.Debug(ctx, fmt.Sprintf("@diff failed to fetch rows: %s", err.Error()))
+					err = d.SetNewComputed("remote_rows")
+					if err != nil {
+						return err
+					}
+					return d.SetNewComputed("statements")
+				}
+				statements := tableRowStatements(database, table, keys, rowColumns(rows), remoteRows, rows)
+				tflog.Debug(ctx, fmt.Sprintf("@diff statements: %s", statements))
+
+				err = d.SetNew("remote_rows", flattenRows(rows))
+				if err != nil {
+					return err
+				}
+				// statements variable is only for showing diff on planning, and always empty value after applying it.
+				err = d.SetNew("statements", statements)
+				if err != nil {
+					return err
+				}
+			}
+
+			tflog.Debug(ctx, fmt.Sprintf("@diff end"))
+			return nil
+		},
+	}
+}
+
+func resourceAlternatorTableRowsCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Debug(ctx, fmt.Sprintf("@create start"))
+
+	database := d.Get("database").(string)
+	table := d.Get("table").(string)
+
+	diags := applyTableRows(ctx, d, meta, "@create")
+	if diags.HasError() {
+		return diags
+	}
+
+	d.SetId(fmt.Sprintf("%s.%s", database, table))
+
+	tflog.Debug(ctx, fmt.Sprintf("@create end"))
+	return resourceAlternatorTableRowsRead(ctx, d, meta)
+}
+
+func resourceAlternatorTableRowsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Debug(ctx, fmt.Sprintf("@read start"))
+
+	database := d.Get("database").(string)
+	table := d.Get("table").(string)
+	keys := expandStringList(d.Get("keys"))
+	rows := expandRows(d.Get("rows"))
+	pp := meta.(*ProviderArguments)
+	// cf. resourceAlternatorDatabaseSchemaRead
+	if pp.Host == "" {
+		tflog.Debug(ctx, fmt.Sprintf("@read host is empty. arguments: %+v", pp))
+		d.SetId("")
+		return nil
+	}
+
+	client, err := newAlternator(database, pp)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer client.Close()
+
+	remoteRows, err := fetchTableRows(ctx, client.Db, database, table, keys)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	columns := rowColumns(rows)
+	remoteRows = orderRows(keys, projectRows(remoteRows, columns), rows)
+	changed := len(tableRowStatements(database, table, keys, columns, remoteRows, rows)) > 0
+
+	tflog.Debug(ctx, fmt.Sprintf("@read remote_rows: %+v", remoteRows))
+	tflog.Debug(ctx, fmt.Sprintf("@read changed: %t", changed))
+
+	err = d.Set("remote_rows", flattenRows(remoteRows))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	err = d.Set("changed", changed)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	err = d.Set("statements", []string{})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	tflog.Debug(ctx, fmt.Sprintf("@read end"))
+	return nil
+}
+
+func resourceAlternatorTableRowsUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Debug(ctx, fmt.Sprintf("@update start"))
+
+	diags := applyTableRows(ctx, d, meta, "@update")
+	if diags.HasError() {
+		return diags
+	}
+
+	tflog.Debug(ctx, fmt.Sprintf("@update end"))
+	return resourceAlternatorTableRowsRead(ctx, d, meta)
+}
+
+func resourceAlternatorTableRowsDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Debug(ctx, fmt.Sprintf("@delete start"))
+
+	database := d.Get("database").(string)
+	table := d.Get("table").(string)
+	keys := expandStringList(d.Get("keys"))
+	rows := expandRows(d.Get("rows"))
+	pp := meta.(*ProviderArguments)
+
+	client, err := newAlternator(database, pp)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer client.Close()
+
+	// Only the managed rows are deleted, so that rows added outside of Terraform after destroy are kept
+	statements := []string{}
+	for _, r := range rows {
+		statements = append(statements, deleteRowStatement(database, table, keys, r))
+	}
+	err = execInTransaction(ctx, client.Db, statements, "@delete")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+
+	tflog.Debug(ctx, fmt.Sprintf("@delete end"))
+	return nil
+}
+
+func resourceAlternatorTableRowsImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	id := d.Id()
+	tflog.Debug(ctx, fmt.Sprintf("@import id = %s", id))
+
+	database, table, ok := strings.Cut(id, ".")
+	if !ok || database == "" || table == "" {
+		return nil, fmt.Errorf("import ID must be in the form of \"database.table\": %s", id)
+	}
+	pp := meta.(*ProviderArguments)
+
+	client, err := newAlternator(database, pp)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	keys, err := fetchPrimaryKeyColumns(client.Db, database, table)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("table %s.%s does not exist or has no primary key", database, table)
+	}
+	rows, err := fetchTableRows(ctx, client.Db, database, table, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	err = d.Set("database", database)
+	if err != nil {
+		return nil, err
+	}
+	err = d.Set("table", table)
+	if err != nil {
+		return nil, err
+	}
+	err = d.Set("keys", keys)
+	if err != nil {
+		return nil, err
+	}
+	err = d.Set("rows", flattenRows(rows))
+	if err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+func applyTableRows(ctx context.Context, d *schema.ResourceData, meta interface{}, label string) diag.Diagnostics {
+	database := d.Get("database").(string)
+	table := d.Get("table").(string)
+	keys := expandStringList(d.Get("keys"))
+	rows := expandRows(d.Get("rows"))
+	pp := meta.(*ProviderArguments)
+
+	err := validateRowKeys(keys, rows)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	client, err := newAlternator(database, pp)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer client.Close()
+
+	remoteRows, err := fetchTableRows(ctx, client.Db, database, table, keys)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	err = execInTransaction(ctx, client.Db, tableRowStatements(database, table, keys, rowColumns(rows), remoteRows, rows), label)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+// fetchTableRows returns all rows of the table ordered by the key columns.
+func fetchTableRows(ctx context.Context, db *sql.DB, database string, table string, keys []string) ([]map[string]string, error) {
+	return queryRows(ctx, db, "", fmt.Sprintf("SELECT * FROM `%s`.`%s` ORDER BY %s", database, table, quoteColumns(keys)))
+}
+
+func fetchPrimaryKeyColumns(db *sql.DB, database string, table string) ([]string, error) {
+	rows, err := db.Query("SELECT COLUMN_NAME FROM information_schema.STATISTICS WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND INDEX_NAME = 'PRIMARY' ORDER BY SEQ_IN_INDEX", database, table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query primary key of %s : %w", table, err)
+	}
+	defer rows.Close()
+	ret := []string{}
+	for rows.Next() {
+		var c string
+		err := rows.Scan(&c)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query primary key of %s : %w", table, err)
+		}
+		ret = append(ret, c)
+	}
+	return ret, rows.Err()
+}
+
+func execInTransaction(ctx context.Context, db *sql.DB, statements []string, label string) error {
+	if len(statements) == 0 {
+		return nil
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	for _, s := range statements {
+		tflog.Info(ctx, fmt.Sprintf("%s executing statements: %s", label, s))
+		_, err := tx.ExecContext(ctx, s)
+		if err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func validateRowKeys(keys []string, rows []map[string]string) error {
+	seen := map[string]bool{}
+	for i, r := range rows {
+		for _, k := range keys {
+			if _, ok := r[k]; !ok {
+				return fmt.Errorf("rows.%d does not have key column %s", i, k)
+			}
+		}
+		rk := rowKey(keys, r)
+		if seen[rk] {
+			return fmt.Errorf("rows.%d has duplicate keys", i)
+		}
+		seen[rk] = true
+	}
+	return nil
+}
+
+// tableRowStatements returns statements to make the remote rows identical to the local ones.
+// Only the given columns are compared and updated. Deletes come first to avoid unique key conflicts.
+func tableRowStatements(database string, table string, keys []string, columns []string, remote []map[string]string, local []map[string]string) []string {
+	remoteByKey := map[string]map[string]string{}
+	for _, r := range remote {
+		remoteByKey[rowKey(keys, r)] = r
+	}
+	localByKey := map[string]bool{}
+	for _, r := range local {
+		localByKey[rowKey(keys, r)] = true
+	}
+
+	deletes := []string{}
+	for _, r := range remote {
+		if !localByKey[rowKey(keys, r)] {
+			deletes = append(deletes, deleteRowStatement(database, table, keys, r))
+		}
+	}
+	updates := []string{}
+	inserts := []string{}
+	for _, l := range local {
+		r, ok := remoteByKey[rowKey(keys, l)]
+		if !ok {
+			values := []string{}
+			for _, c := range columns {
+				values = append(values, rowValue(l, c))
+			}
+			inserts = append(inserts, fmt.Sprintf("INSERT INTO `%s`.`%s` (%s) VALUES (%s)", database, table, quoteColumns(columns), strings.Join(values, ", ")))
+			continue
+		}
+		sets := []string{}
+		for _, c := range columns {
+			lv, lok := l[c]
+			rv, rok := r[c]
+			if lok != rok || lv != rv {
+				sets = append(sets, fmt.Sprintf("`%s` = %s", c, rowValue(l, c)))
+			}
+		}
+		if len(sets) > 0 {
+			updates = append(updates, fmt.Sprintf("UPDATE `%s`.`%s` SET %s WHERE %s", database, table, strings.Join(sets, ", "), rowCondition(keys, r)))
+		}
+	}
+	return append(append(deletes, updates...), inserts...)
+}
+
+func deleteRowStatement(database string, table string, keys []string, row map[string]string) string {
+	return fmt.Sprintf("DELETE FROM `%s`.`%s` WHERE %s", database, table, rowCondition(keys, row))
+}
+
+func rowCondition(keys []string, row map[string]string) string {
+	conditions := []string{}
+	for _, k := range keys {
+		if v, ok := row[k]; ok {
+			conditions = append(conditions, fmt.Sprintf("`%s` = %s", k, quoteString(v)))
+		} else {
+			conditions = append(conditions, fmt.Sprintf("`%s` IS NULL", k))
+		}
+	}
+	return strings.Join(conditions, " AND ")
+}
+
+func rowValue(row map[string]string, column string) string {
+	if v, ok := row[column]; ok {
+		return quoteString(v)
+	}
+	return "NULL"
+}
+
+// rowKey returns a string uniquely identifying the row by the key columns.
+func rowKey(keys []string, row map[string]string) string {
+	values := []string{}
+	for _, k := range keys {
+		if v, ok := row[k]; ok {
+			values = append(values, quoteString(v))
+		} else {
+			values = append(values, "NULL")
+		}
+	}
+	return strings.Join(values, ",")
+}
+
+// rowColumns returns the sorted union of the columns of the rows.
+func rowColumns(rows []map[string]string) []string {
+	set := map[string]bool{}
+	for _, r := range rows {
+		for c := range r {
+			set[c] = true
+		}
+	}
+	ret := []string{}
+	for c := range set {
+		ret = append(ret, c)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+func projectRows(rows []map[string]string, columns []string) []map[string]string {
+	ret := []map[string]string{}
+	for _, r := range rows {
+		m := map[string]string{}
+		for _, c := range columns {
+			if v, ok := r[c]; ok {
+				m[c] = v
+			}
+		}
+		ret = append(ret, m)
+	}
+	return ret
+}
+
+// orderRows sorts the rows in the same order as the corresponding reference rows, so that remote_rows is compared with rows
+// element by element. Rows without corresponding ones are placed at the end.
+func orderRows(keys []string, rows []map[string]string, reference []map[string]string) []map[string]string {
+	index := map[string]int{}
+	for i, r := range reference {
+		index[rowKey(keys, r)] = i
+	}
+	ret := append([]map[string]string{}, rows...)
+	sort.SliceStable(ret, func(i, j int) bool {
+		ii, iok := index[rowKey(keys, ret[i])]
+		ji, jok := index[rowKey(keys, ret[j])]
+		if iok && jok {
+			return ii < ji
+		}
+		return iok && !jok
+	})
+	return ret
+}
+
+func quoteColumns(columns []string) string {
+	ret := []string{}
+	for _, c := range columns {
+		ret = append(ret, fmt.Sprintf("`%s`", c))
+	}
+	return strings.Join(ret, ", ")
+}
+
+func expandStringList(v interface{}) []string {
+	ret := []string{}
+	for _, e := range v.([]interface{}) {
+		ret = append(ret, e.(string))
+	}
+	return ret
+}
+
+func expandRows(v interface{}) []map[string]string {
+	ret := []map[string]string{}
+	for _, e := range v.([]interface{}) {
+		if e == nil {
+			ret = append(ret, map[string]string{})
+			continue
+		}
+		ret = append(ret, expandStringMap(e))
+	}
+	return ret
+}
@@ -0,0 +1,107 @@
+package provider
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestAccResourceAlternatorTableRows(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			// Create
+			{
+				PreConfig: func() {
+					db, err := sql.Open("mysql", "root@tcp(localhost:23306)/?multiStatements=true")
+					require.NoError(t, err)
+					_, err = db.Exec("DROP DATABASE IF EXISTS example8; CREATE DATABASE example8; " +
+						"CREATE TABLE example8.plans (code varchar(10) PRIMARY KEY, name varchar(100), price int, created_at datetime DEFAULT CURRENT_TIMESTAMP); " +
+						"INSERT INTO example8.plans (code, name, price) VALUES ('old', 'Old', 0)")
+					require.NoError(t, err)
+				},
+				Config: testAccResourceAlternatorTableRowsConfig("Free", "Pro"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("alternator_table_rows.main", "remote_rows.#", "2"),
+					resource.TestCheckResourceAttr("alternator_table_rows.main", "remote_rows.1.name", "Pro"),
+					resource.TestCheckResourceAttr("alternator_table_rows.main", "statements.#", "0"),
+				),
+			},
+			// Update
+			{
+				Config: testAccResourceAlternatorTableRowsConfig("Free", "Professional"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("alternator_table_rows.main", "remote_rows.1.name", "Professional"),
+				),
+			},
+			// Rows changed outside of Terraform
+			{
+				PreConfig: func() {
+					db, err := sql.Open("mysql", "root@tcp(localhost:23306)/")
+					require.NoError(t, err)
+					_, err = db.Exec("INSERT INTO example8.plans (code, name, price) VALUES ('extra', 'Extra', 1)")
+					require.NoError(t, err)
+				},
+				Config:             testAccResourceAlternatorTableRowsConfig("Free", "Professional"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			// Import
+			{
+				ResourceName:            "alternator_table_rows.main",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"rows", "remote_rows", "changed"},
+			},
+		},
+	})
+}
+
+func testAccResourceAlternatorTableRowsConfig(free string, pro string) string {
+	return fmt.Sprintf(`
+    %s
+	resource "alternator_table_rows" "main" {
+        database = "example8"
+        table    = "plans"
+        keys     = ["code"]
+        rows = [
+            { code = "free", name = "%s", price = "0" },
+            { code = "pro", name = "%s", price = "10" },
+        ]
+	}
+	`, provider, free, pro)
+}
+
+func TestTableRowStatements(t *testing.T) {
+	remote := []map[string]string{
+		{"code": "free", "name": "Free", "price": "0"},
+		{"code": "old", "name": "Old", "price": "5"},
+		{"code": "pro", "name": "Pro", "price": "10"},
+	}
+	local := []map[string]string{
+		{"code": "free", "name": "Free", "price": "0"},
+		{"code": "pro", "name": "Professional"},
+		{"code": "team", "name": "Team's", "price": "20"},
+	}
+	require.Equal(t, []string{
+		"DELETE FROM `example`.`plans` WHERE `code` = 'old'",
+		"UPDATE `example`.`plans` SET `name` = 'Professional', `price` = NULL WHERE `code` = 'pro'",
+		"INSERT INTO `example`.`plans` (`code`, `name`, `price`) VALUES ('team', 'Team''s', '20')",
+	}, tableRowStatements("example", "plans", []string{"code"}, rowColumns(local), remote, local))
+
+	require.Empty(t, tableRowStatements("example", "plans", []string{"code"}, rowColumns(local), local, local))
+}
+
+func TestOrderRows(t *testing.T) {
+	rows := []map[string]string{{"code": "a"}, {"code": "b"}, {"code": "c"}}
+	reference := []map[string]string{{"code": "c"}, {"code": "a"}}
+	require.Equal(t, []map[string]string{{"code": "c"}, {"code": "a"}, {"code": "b"}}, orderRows([]string{"code"}, rows, reference))
+}
+
+func TestValidateRowKeys(t *testing.T) {
+	require.NoError(t, validateRowKeys([]string{"code"}, []map[string]string{{"code": "a"}, {"code": "b"}}))
+	require.Error(t, validateRowKeys([]string{"code"}, []map[string]string{{"name": "a"}}))
+	require.Error(t, validateRowKeys([]string{"code"}, []map[string]string{{"code": "a"}, {"code": "a"}}))
+}
@@ -0,0 +1,98 @@
+package provider
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/require"
+	"regexp"
+	"testing"
+)
+
+func TestAccResourceAlternatorTable(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			// Create
+			{
+				PreConfig: func() {
+					db, err := sql.Open("mysql", "root@tcp(localhost:23306)/?multiStatements=true")
+					require.NoError(t, err)
+					_, err = db.Exec("DROP DATABASE IF EXISTS example3")
+					require.NoError(t, err)
+					// table not managed by the resource
+					_, err = db.Exec("CREATE DATABASE example3; CREATE TABLE example3.other (id int PRIMARY KEY)")
+					require.NoError(t, err)
+				},
+				Config: testAccResourceAlternatorTableConfig("greeting", "varchar(255)"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("alternator_table.main", "id", "example3.greeting"),
+					resource.TestMatchResourceAttr("alternator_table.main", "remote_definition", regexp.MustCompile("`body`\\s+varchar\\(255\\)")),
+				),
+			},
+			// Update definition
+			{
+				Config: testAccResourceAlternatorTableConfig("greeting", "varchar(256)"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("alternator_table.main", "remote_definition", regexp.MustCompile("`body`\\s+varchar\\(256\\)")),
+					testAccCheckTableExists("example3", "other"),
+				),
+			},
+			// Rename table
+			{
+				Config: testAccResourceAlternatorTableConfig("greetings", "varchar(256)"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("alternator_table.main", "id", "example3.greetings"),
+					testAccCheckTableExists("example3", "greetings"),
+					testAccCheckTableExists("example3", "other"),
+				),
+			},
+			// Import
+			{
+				Config:            testAccResourceAlternatorTableConfig("greetings", "varchar(256)"),
+				ResourceName:      "alternator_table.main",
+				ImportState:       true,
+				ImportStateId:     "example3.greetings",
+				ImportStateVerify: true,
+				ImportStateVerifyIgnore: []string{
+					"definition",
+				},
+			},
+		},
+	})
+}
+
+func testAccCheckTableExists(database string, table string) resource.TestCheckFunc {
+	return func(state *terraform.State) error {
+		db, err := sql.Open("mysql", "root@tcp(localhost:23306)/")
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+		var name string
+		err = db.QueryRow("SELECT table_name FROM information_schema.tables WHERE table_schema = ? AND table_name = ?", database, table).Scan(&name)
+		if err != nil {
+			return fmt.Errorf("table %s.%s not found: %w", database, table, err)
+		}
+		return nil
+	}
+}
+
+func testAccResourceAlternatorTableConfig(name string, bodyType string) string {
+	return fmt.Sprintf(`
+    %s
+	resource "alternator_table" "main" {
+        database   = "example3"
+        name       = "%s"
+        definition = <<EOT
+		CREATE TABLE %s
+		(
+		    id   int AUTO_INCREMENT,
+		    body %s,
+		    PRIMARY KEY (id)
+		);
+		EOT
+	}
+	`, provider, name, name, bodyType)
+}
@@ -0,0 +1,294 @@
+package provider
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"strings"
+)
+
+// Resource limit arguments and the corresponding columns of mysql.user
+var userResourceLimits = []struct {
+	Name   string
+	Column string
+	Option string
+}{
+	{"max_queries_per_hour", "max_questions", "MAX_QUERIES_PER_HOUR"},
+	{"max_updates_per_hour", "max_updates", "MAX_UPDATES_PER_HOUR"},
+	{"max_connections_per_hour", "max_connections", "MAX_CONNECTIONS_PER_HOUR"},
+	{"max_user_connections", "max_user_connections", "MAX_USER_CONNECTIONS"},
+}
+
+func resourceAlternatorUser() *schema.Resource {
+	s := map[string]*schema.Schema{
+		"user": {
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+			Description: "User name.",
+		},
+		"host": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			ForceNew:    true,
+			Default:     "%",
+			Description: "Host name pattern from which the user can connect.",
+		},
+		"auth_plugin": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Computed:    true,
+			Description: "Authentication plugin such as `caching_sha2_password`. The server default is used if not specified.",
+		},
+		"password": {
+			Type:          schema.TypeString,
+			Optional:      true,
+			Sensitive:     true,
+			ConflictsWith: []string{"password_wo"},
+			Description:   "Password of the user. It is stored in the state as plain text, so consider using `password_wo` instead.",
+		},
+		"password_wo": {
+			Type:          schema.TypeString,
+			Optional:      true,
+			WriteOnly:     true,
+			ConflictsWith: []string{"password"},
+			Description:   "Password of the user, which is never stored in the plan or the state. Requires Terraform 1.11 or later. Change `password_wo_version` to update the password.",
+		},
+		"password_wo_version": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			RequiredWith: []string{"password_wo"},
+			Description:  "Version of `password_wo`. Changing it updates the password.",
+		},
+	}
+	for _, l := range userResourceLimits {
+		s[l.Name] = &schema.Schema{
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Default:      0,
+			ValidateFunc: validation.IntAtLeast(0),
+			Description:  fmt.Sprintf("Resource limit `%s`. 0 means no limit.", l.Option),
+		}
+	}
+
+	return &schema.Resource{
+		Description:   "Manage a user account of SQL database.",
+		CreateContext: resourceAlternatorUserCreate,
+		ReadContext:   resourceAlternatorUserRead,
+		UpdateContext: resourceAlternatorUserUpdate,
+		DeleteContext: resourceAlternatorUserDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceAlternatorUserImport,
+		},
+		Schema: s,
+	}
+}
+
+func resourceAlternatorUserCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Debug(ctx, fmt.Sprintf("@create start"))
+
+	user := d.Get("user").(string)
+	host := d.Get("host").(string)
+	pp := meta.(*ProviderArguments)
+
+	client, err := newAlternator("", pp)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer client.Close()
+
+	statement := fmt.Sprintf("CREATE USER %s%s%s", quoteAccount(user, host), userIdentification(d, true), userResourceOptions(d, true))
+	tflog.Info(ctx, fmt.Sprintf("@create executing statements: CREATE USER %s", quoteAccount(user, host)))
+	_, err = client.Db.Exec(statement)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(fmt.Sprintf("%s@%s", user, host))
+
+	tflog.Debug(ctx, fmt.Sprintf("@create end"))
+	return resourceAlternatorUserRead(ctx, d, meta)
+}
+
+func resourceAlternatorUserRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Debug(ctx, fmt.Sprintf("@read start"))
+
+	user := d.Get("user").(string)
+	host := d.Get("host").(string)
+	pp := meta.(*ProviderArguments)
+	// cf. resourceAlternatorDatabaseSchemaRead
+	if pp.Host == "" {
+		tflog.Debug(ctx, fmt.Sprintf("@read host is empty. arguments: %+v", pp))
+		d.SetId("")
+		return nil
+	}
+
+	client, err := newAlternator("", pp)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer client.Close()
+
+	columns := []string{"plugin"}
+	for _, l := range userResourceLimits {
+		columns = append(columns, l.Column)
+	}
+	var plugin string
+	limits := make([]int, len(userResourceLimits))
+	dest := []interface{}{&plugin}
+	for i := range limits {
+		dest = append(dest, &limits[i])
+	}
+	err = client.Db.QueryRow(fmt.Sprintf("SELECT %s FROM mysql.user WHERE User = ? AND Host = ?", strings.Join(columns, ", ")), user, host).Scan(dest...)
+	if errors.Is(err, sql.ErrNoRows) {
+		tflog.Warn(ctx, fmt.Sprintf("@read user %s@%s not found, removing from state", user, host))
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = d.Set("auth_plugin", plugin)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	for i, l := range userResourceLimits {
+		err = d.Set(l.Name, limits[i])
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	tflog.Debug(ctx, fmt.Sprintf("@read end"))
+	return nil
+}
+
+func resourceAlternatorUserUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Debug(ctx, fmt.Sprintf("@update start"))
+
+	user := d.Get("user").(string)
+	host := d.Get("host").(string)
+	pp := meta.(*ProviderArguments)
+
+	client, err := newAlternator("", pp)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer client.Close()
+
+	identification := ""
+	if d.HasChanges("auth_plugin", "password", "password_wo_version") {
+		identification = userIdentification(d, false)
+	}
+	resourceOptions := userResourceOptions(d, false)
+	if identification != "" || resourceOptions != "" {
+		tflog.Info(ctx, fmt.Sprintf("@update executing statements: ALTER USER %s", quoteAccount(user, host)))
+		_, err = client.Db.Exec(fmt.Sprintf("ALTER USER %s%s%s", quoteAccount(user, host), identification, resourceOptions))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	tflog.Debug(ctx, fmt.Sprintf("@update end"))
+	return resourceAlternatorUserRead(ctx, d, meta)
+}
+
+func resourceAlternatorUserDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Debug(ctx, fmt.Sprintf("@delete start"))
+
+	user := d.Get("user").(string)
+	host := d.Get("host").(string)
+	pp := meta.(*ProviderArguments)
+
+	client, err := newAlternator("", pp)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer client.Close()
+
+	statement := fmt.Sprintf("DROP USER IF EXISTS %s", quoteAccount(user, host))
+	tflog.Info(ctx, fmt.Sprintf("@delete executing statements: %s", statement))
+	_, err = client.Db.Exec(statement)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+
+	tflog.Debug(ctx, fmt.Sprintf("@delete end"))
+	return nil
+}
+
+func resourceAlternatorUserImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	id := d.Id()
+	tflog.Debug(ctx, fmt.Sprintf("@import id = %s", id))
+
+	// User name may contain "@", but host may not
+	i := strings.LastIndex(id, "@")
+	if i <= 0 || i == len(id)-1 {
+		return nil, fmt.Errorf("import ID must be in the form of \"user@host\": %s", id)
+	}
+	err := d.Set("user", id[:i])
+	if err != nil {
+		return nil, err
+	}
+	err = d.Set("host", id[i+1:])
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range userResourceLimits {
+		err = d.Set(l.Name, 0)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+// userIdentification returns IDENTIFIED clause of CREATE USER or ALTER USER statement.
+func userIdentification(d *schema.ResourceData, create bool) string {
+	password := ""
+	hasPassword := false
+	// Write-only password is available only in the configuration
+	if v := d.GetRawConfig().GetAttr("password_wo"); !v.IsNull() {
+		password = v.AsString()
+		hasPassword = create || d.HasChange("password_wo_version")
+	} else if v, ok := d.GetOk("password"); ok {
+		password = v.(string)
+		hasPassword = create || d.HasChange("password")
+	}
+	// Specify the plugin with the password, so that the password is hashed by the plugin
+	plugin := ""
+	if create || hasPassword || d.HasChange("auth_plugin") {
+		plugin = d.Get("auth_plugin").(string)
+	}
+
+	switch {
+	case plugin != "" && hasPassword:
+		return fmt.Sprintf(" IDENTIFIED WITH %s BY %s", plugin, quoteString(password))
+	case plugin != "":
+		return fmt.Sprintf(" IDENTIFIED WITH %s", plugin)
+	case hasPassword:
+		return fmt.Sprintf(" IDENTIFIED BY %s", quoteString(password))
+	}
+	return ""
+}
+
+// userResourceOptions returns resource options of CREATE USER or ALTER USER statement.
+func userResourceOptions(d *schema.ResourceData, create bool) string {
+	options := []string{}
+	for _, l := range userResourceLimits {
+		if create || d.HasChange(l.Name) {
+			options = append(options, fmt.Sprintf("%s %d", l.Option, d.Get(l.Name).(int)))
+		}
+	}
+	if len(options) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" WITH %s", strings.Join(options, " "))
+}
@@ -0,0 +1,127 @@
+package provider
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestAccResourceAlternatorUser(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			// Create
+			{
+				PreConfig: func() {
+					db, err := sql.Open("mysql", "root@tcp(localhost:23306)/")
+					require.NoError(t, err)
+					_, err = db.Exec("DROP USER IF EXISTS 'app'@'%'")
+					require.NoError(t, err)
+				},
+				Config: testAccResourceAlternatorUserConfig(`
+					password = "secret1"
+				`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("alternator_user.main", "id", "app@%"),
+					resource.TestCheckResourceAttr("alternator_user.main", "auth_plugin", "caching_sha2_password"),
+					testAccCheckLogin("app", "secret1"),
+				),
+			},
+			// Update password and resource limits
+			{
+				Config: testAccResourceAlternatorUserConfig(`
+					password             = "secret2"
+					max_user_connections = 10
+				`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("alternator_user.main", "max_user_connections", "10"),
+					testAccCheckLogin("app", "secret2"),
+				),
+			},
+			// Import
+			{
+				Config: testAccResourceAlternatorUserConfig(`
+					password             = "secret2"
+					max_user_connections = 10
+				`),
+				ResourceName:      "alternator_user.main",
+				ImportState:       true,
+				ImportStateId:     "app@%",
+				ImportStateVerify: true,
+				ImportStateVerifyIgnore: []string{
+					"password",
+				},
+			},
+		},
+	})
+}
+
+func TestAccResourceAlternatorUserWriteOnlyPassword(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			// Create
+			{
+				PreConfig: func() {
+					db, err := sql.Open("mysql", "root@tcp(localhost:23306)/")
+					require.NoError(t, err)
+					_, err = db.Exec("DROP USER IF EXISTS 'app'@'%'")
+					require.NoError(t, err)
+				},
+				Config: testAccResourceAlternatorUserConfig(`
+					password_wo         = "secret1"
+					password_wo_version = 1
+				`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckNoResourceAttr("alternator_user.main", "password_wo"),
+					resource.TestCheckNoResourceAttr("alternator_user.main", "password"),
+					resource.TestCheckResourceAttr("alternator_user.main", "password_wo_version", "1"),
+					testAccCheckLogin("app", "secret1"),
+				),
+			},
+			// Password is not updated unless the version is changed
+			{
+				Config: testAccResourceAlternatorUserConfig(`
+					password_wo         = "secret2"
+					password_wo_version = 1
+				`),
+				PlanOnly: true,
+			},
+			// Update password
+			{
+				Config: testAccResourceAlternatorUserConfig(`
+					password_wo         = "secret2"
+					password_wo_version = 2
+				`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckNoResourceAttr("alternator_user.main", "password_wo"),
+					testAccCheckLogin("app", "secret2"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckLogin(user string, password string) resource.TestCheckFunc {
+	return func(state *terraform.State) error {
+		db, err := sql.Open("mysql", fmt.Sprintf("%s:%s@tcp(localhost:23306)/", user, password))
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+		return db.Ping()
+	}
+}
+
+func testAccResourceAlternatorUserConfig(options string) string {
+	return fmt.Sprintf(`
+    %s
+	resource "alternator_user" "main" {
+        user = "app"
+        %s
+	}
+	`, provider, options)
+}
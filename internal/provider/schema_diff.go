@@ -0,0 +1,94 @@
+package provider
+
+import (
+	"fmt"
+	"github.com/kota65535/alternator/cmd"
+	"github.com/kota65535/alternator/lib"
+)
+
+// schemaDiff is the difference between the remote database and the schema argument,
+// including schema objects other than tables.
+type schemaDiff struct {
+	Alterations   *lib.DatabaseAlterations
+	RemoteSchemas []*lib.Schema
+	LocalSchemas  []*lib.Schema
+	RemoteObjects []*schemaObject
+	LocalObjects  []*schemaObject
+	// Changes in the order of execution
+	Changes []*plannedChange
+}
+
+// getSchemaDiff calculates the changes to make the remote database match the schema.
+// previousSchemaStr is the schema applied last time, used to detect changes of schema objects. cf. schemaObjectChanges
+func getSchemaDiff(client *cmd.Alternator, database string, schemaStr string, previousSchemaStr string, renames []*schemaRename, ignorePartitions []string) (*schemaDiff, error) {
+	tableSchemaStr, localObjects := splitSchema(schemaStr)
+	_, previousObjects := splitSchema(previousSchemaStr)
+
+	alt, renameChanges, remoteSchemas, localSchemas, err := getAlterationsWithRenames(client, tableSchemaStr, renames, ignorePartitions)
+	if err != nil {
+		return nil, err
+	}
+	remoteObjects, err := fetchSchemaObjects(client.Db, database)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch remote schema objects : %w", err)
+	}
+	preObjectChanges, postObjectChanges := schemaObjectChanges(database, remoteObjects, previousObjects, localObjects)
+
+	// Renames are executed before other alterations.
+	// Schema objects may depend on tables, so they are dropped before altering tables and created after that.
+	changes := []*plannedChange{}
+	changes = append(changes, preObjectChanges...)
+	changes = append(changes, renameChanges...)
+	changes = append(changes, plannedChanges(alt)...)
+	changes = append(changes, postObjectChanges...)
+
+	return &schemaDiff{
+		Alterations:   alt,
+		RemoteSchemas: remoteSchemas,
+		LocalSchemas:  localSchemas,
+		RemoteObjects: remoteObjects,
+		LocalObjects:  localObjects,
+		Changes:       changes,
+	}, nil
+}
+
+func (r *schemaDiff) Statements() []string {
+	return plannedChangeStatements(r.Changes)
+}
+
+func plannedChangeStatements(changes []*plannedChange) []string {
+	ret := []string{}
+	for _, c := range changes {
+		ret = append(ret, c.Sql)
+	}
+	return ret
+}
+
+// HasObjectChanges returns true if any schema object other than tables is changed.
+func (r *schemaDiff) HasObjectChanges() bool {
+	for _, c := range r.Changes {
+		if c.schemaObject != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// RemoteSchemaString returns the remote schema definition.
+func (r *schemaDiff) RemoteSchemaString() string {
+	ret := ""
+	for _, s := range r.Alterations.FromString() {
+		ret += fmt.Sprintf("%s\n", s)
+	}
+	return ret + formatSchemaObjects(r.RemoteObjects)
+}
+
+// LocalSchemaString returns the remote schema definition expected after applying the changes.
+// Schema objects are the remote ones, so it is valid only if they have no changes.
+func (r *schemaDiff) LocalSchemaString() string {
+	ret := ""
+	for _, s := range r.Alterations.ToString() {
+		ret += fmt.Sprintf("%s\n", s)
+	}
+	return ret + formatSchemaObjects(r.RemoteObjects)
+}
@@ -0,0 +1,336 @@
+package provider
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"regexp"
+	"strings"
+)
+
+// schemaObject is a schema object other than tables, which is not supported by Alternator.
+type schemaObject struct {
+	// One of "view", "trigger", "procedure", "function" or "event"
+	Type string
+	Name string
+	// Table of the trigger
+	Table      string
+	Definition string
+}
+
+var (
+	delimiterPattern = regexp.MustCompile(`(?i)^[ \t]*DELIMITER[ \t]+(\S+)[ \t]*\r?$`)
+	objectPattern    = regexp.MustCompile("(?is)^CREATE\\s+(?:OR\\s+REPLACE\\s+)?(?:ALGORITHM\\s*=\\s*\\w+\\s+)?(?:DEFINER\\s*=\\s*\\S+\\s+)?(?:SQL\\s+SECURITY\\s+\\w+\\s+)?" +
+		"(VIEW|TRIGGER|PROCEDURE|FUNCTION|EVENT)\\s+(?:IF\\s+NOT\\s+EXISTS\\s+)?((?:`[^`]+`|\\w+)(?:\\s*\\.\\s*(?:`[^`]+`|\\w+))?)")
+	triggerTablePattern = regexp.MustCompile("(?is)^\\s*(?:BEFORE|AFTER)\\s+(?:INSERT|UPDATE|DELETE)\\s+ON\\s+((?:`[^`]+`|\\w+)(?:\\s*\\.\\s*(?:`[^`]+`|\\w+))?)")
+	createPattern       = regexp.MustCompile(`(?is)^CREATE\s+(?:OR\s+REPLACE\s+)?`)
+)
+
+// Queries to list the schema objects of a database, and the columns of SHOW CREATE statements containing their definitions.
+// Functions and procedures come first, because views and triggers may call them.
+var schemaObjectQueries = []struct {
+	Type   string
+	List   string
+	Column string
+}{
+	{"function", "SELECT ROUTINE_NAME, '' FROM information_schema.ROUTINES WHERE ROUTINE_SCHEMA = ? AND ROUTINE_TYPE = 'FUNCTION' ORDER BY ROUTINE_NAME", "Create Function"},
+	{"procedure", "SELECT ROUTINE_NAME, '' FROM information_schema.ROUTINES WHERE ROUTINE_SCHEMA = ? AND ROUTINE_TYPE = 'PROCEDURE' ORDER BY ROUTINE_NAME", "Create Procedure"},
+	{"view", "SELECT TABLE_NAME, '' FROM information_schema.VIEWS WHERE TABLE_SCHEMA = ? ORDER BY TABLE_NAME", "Create View"},
+	{"trigger", "SELECT TRIGGER_NAME, EVENT_OBJECT_TABLE FROM information_schema.TRIGGERS WHERE TRIGGER_SCHEMA = ? ORDER BY EVENT_OBJECT_TABLE, ACTION_ORDER", "SQL Original Statement"},
+	{"event", "SELECT EVENT_NAME, '' FROM information_schema.EVENTS WHERE EVENT_SCHEMA = ? ORDER BY EVENT_NAME", "Create Event"},
+}
+
+func (o *schemaObject) key() string {
+	return fmt.Sprintf("%s %s", o.Type, o.Name)
+}
+
+// splitStatements splits the SQL into statements, in the same way as mysql client does.
+// Delimiters in quotes and comments are ignored, and DELIMITER command changes the delimiter
+// so that statements with BEGIN ... END block can be written.
+func splitStatements(str string) []string {
+	ret := []string{}
+	delimiter := ";"
+	start := 0
+	flush := func(end int) {
+		if s := strings.TrimSpace(str[start:end]); s != "" {
+			ret = append(ret, s)
+		}
+	}
+	for i := 0; i < len(str); i++ {
+		// DELIMITER command is only recognized at the beginning of a statement
+		if (i == 0 || str[i-1] == '\n') && stripLeadingComments(str[start:i]) == "" {
+			end := strings.IndexByte(str[i:], '\n')
+			if end < 0 {
+				end = len(str) - i
+			}
+			if m := delimiterPattern.FindStringSubmatch(str[i : i+end]); m != nil {
+				delimiter = m[1]
+				i += end
+				start = i
+				continue
+			}
+		}
+		switch c := str[i]; {
+		case c == '\'' || c == '"' || c == '`':
+			for i++; i < len(str) && str[i] != c; i++ {
+				if str[i] == '\\' && c != '`' {
+					i++
+				}
+			}
+		case c == '#' || strings.HasPrefix(str[i:], "-- "):
+			for ; i < len(str) && str[i] != '\n'; i++ {
+			}
+		case strings.HasPrefix(str[i:], "/*"):
+			end := strings.Index(str[i+2:], "*/")
+			if end < 0 {
+				i = len(str)
+			} else {
+				i += end + 3
+			}
+		case strings.HasPrefix(str[i:], delimiter):
+			flush(i)
+			i += len(delimiter) - 1
+			start = i + 1
+		}
+	}
+	if start < len(str) {
+		flush(len(str))
+	}
+	return ret
+}
+
+// splitSchema separates schema objects other than tables from the schema.
+// The remaining schema is returned as is if it contains no such objects, so that it can be passed to Alternator.
+func splitSchema(str string) (string, []*schemaObject) {
+	statements := splitStatements(str)
+	tableStatements := []string{}
+	objects := []*schemaObject{}
+	for _, s := range statements {
+		if o := parseSchemaObject(s); o != nil {
+			objects = append(objects, o)
+		} else {
+			tableStatements = append(tableStatements, s)
+		}
+	}
+	if len(objects) == 0 {
+		return str, objects
+	}
+	ret := ""
+	for _, s := range tableStatements {
+		ret += fmt.Sprintf("%s;\n", s)
+	}
+	return ret, objects
+}
+
+// parseSchemaObject parses CREATE statement of a schema object, or returns nil if it is not.
+func parseSchemaObject(statement string) *schemaObject {
+	definition := stripLeadingComments(statement)
+	m := objectPattern.FindStringSubmatchIndex(definition)
+	if m == nil {
+		return nil
+	}
+	ret := &schemaObject{
+		Type:       strings.ToLower(definition[m[2]:m[3]]),
+		Name:       unqualifiedName(definition[m[4]:m[5]]),
+		Definition: definition,
+	}
+	if ret.Type == "trigger" {
+		if t := triggerTablePattern.FindStringSubmatch(definition[m[1]:]); t != nil {
+			ret.Table = unqualifiedName(t[1])
+		}
+	}
+	return ret
+}
+
+func stripLeadingComments(s string) string {
+	for {
+		s = strings.TrimSpace(s)
+		switch {
+		case strings.HasPrefix(s, "#") || strings.HasPrefix(s, "-- "):
+			_, s, _ = strings.Cut(s, "\n")
+		case strings.HasPrefix(s, "/*") && !strings.HasPrefix(s, "/*!"):
+			_, s, _ = strings.Cut(s, "*/")
+		default:
+			return s
+		}
+	}
+}
+
+// unqualifiedName returns the object name without database name and backquotes.
+func unqualifiedName(name string) string {
+	parts := strings.Split(name, ".")
+	return strings.Trim(strings.TrimSpace(parts[len(parts)-1]), "`")
+}
+
+// fetchSchemaObjects fetches definitions of schema objects other than tables in the database.
+func fetchSchemaObjects(db *sql.DB, database string) ([]*schemaObject, error) {
+	ret := []*schemaObject{}
+	for _, q := range schemaObjectQueries {
+		rows, err := db.Query(q.List, database)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %ss : %w", q.Type, err)
+		}
+		objects := []*schemaObject{}
+		for rows.Next() {
+			o := &schemaObject{Type: q.Type}
+			err := rows.Scan(&o.Name, &o.Table)
+			if err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to list %ss : %w", q.Type, err)
+			}
+			objects = append(objects, o)
+		}
+		rows.Close()
+
+		for _, o := range objects {
+			o.Definition, err = showCreate(db, fmt.Sprintf("SHOW CREATE %s `%s`.`%s`", strings.ToUpper(q.Type), database, o.Name), q.Column)
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch %s %s : %w", q.Type, o.Name, err)
+			}
+		}
+		ret = append(ret, objects...)
+	}
+	return ret, nil
+}
+
+// showCreate executes SHOW CREATE statement and returns the value of the column.
+func showCreate(db *sql.DB, query string, column string) (string, error) {
+	rows, err := db.Query(query)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return "", err
+	}
+	if !rows.Next() {
+		return "", fmt.Errorf("no result: %s", query)
+	}
+	values := make([]sql.NullString, len(columns))
+	dest := []interface{}{}
+	for i := range values {
+		dest = append(dest, &values[i])
+	}
+	err = rows.Scan(dest...)
+	if err != nil {
+		return "", err
+	}
+	for i, c := range columns {
+		if c == column {
+			return values[i].String, nil
+		}
+	}
+	return "", fmt.Errorf("column %s not found: %s", column, query)
+}
+
+// formatSchemaObjects formats the schema objects as statements, using DELIMITER command for ones with compound statements.
+func formatSchemaObjects(objects []*schemaObject) string {
+	ret := ""
+	for _, o := range objects {
+		if strings.Contains(o.Definition, ";") {
+			ret += fmt.Sprintf("DELIMITER //\n%s//\nDELIMITER ;\n", o.Definition)
+		} else {
+			ret += fmt.Sprintf("%s;\n", o.Definition)
+		}
+	}
+	return ret
+}
+
+// schemaObjectChanges returns changes to make the remote schema objects match the local ones.
+// The first changes must be executed before table alterations and the second ones after them.
+//
+// Definitions are compared with the previous local ones instead of the remote ones,
+// because the server rewrites some of them, such as SELECT statements of views.
+// So only creation and deletion are detected as drift.
+func schemaObjectChanges(database string, remote []*schemaObject, previous []*schemaObject, local []*schemaObject) ([]*plannedChange, []*plannedChange) {
+	remoteMap := map[string]*schemaObject{}
+	for _, o := range remote {
+		remoteMap[o.key()] = o
+	}
+	previousMap := map[string]*schemaObject{}
+	for _, o := range previous {
+		previousMap[o.key()] = o
+	}
+	localMap := map[string]*schemaObject{}
+	for _, o := range local {
+		localMap[o.key()] = o
+	}
+
+	pre := []*plannedChange{}
+	post := []*plannedChange{}
+	for i := len(remote) - 1; i >= 0; i-- {
+		if _, ok := localMap[remote[i].key()]; !ok {
+			pre = append(pre, dropObjectChange(database, remote[i], "drop", "dropped"))
+		}
+	}
+	for _, l := range local {
+		if _, ok := remoteMap[l.key()]; !ok {
+			post = append(post, createObjectChange(l, l.Definition, "create", "created"))
+			continue
+		}
+		if p, ok := previousMap[l.key()]; ok && normalizeDefinition(p.Definition) == normalizeDefinition(l.Definition) {
+			continue
+		}
+		// Views can be replaced atomically, but others must be dropped before tables are altered
+		if l.Type == "view" {
+			post = append(post, createObjectChange(l, createPattern.ReplaceAllString(l.Definition, "CREATE OR REPLACE "), "alter", "replaced"))
+		} else {
+			pre = append(pre, dropObjectChange(database, l, "alter", "replaced"))
+			post = append(post, createObjectChange(l, l.Definition, "alter", "replaced"))
+		}
+	}
+	return pre, post
+}
+
+func createObjectChange(o *schemaObject, definition string, kind string, action string) *plannedChange {
+	return &plannedChange{
+		Sql:          fmt.Sprintf("%s;", definition),
+		Kind:         kind,
+		ObjectType:   o.Type,
+		Object:       o.Name,
+		Reason:       fmt.Sprintf("%s %s: %s", o.Type, o.Name, action),
+		schemaObject: o,
+	}
+}
+
+func dropObjectChange(database string, o *schemaObject, kind string, action string) *plannedChange {
+	return &plannedChange{
+		Sql:          fmt.Sprintf("DROP %s IF EXISTS `%s`.`%s`;", strings.ToUpper(o.Type), database, o.Name),
+		Kind:         kind,
+		ObjectType:   o.Type,
+		Object:       o.Name,
+		Reason:       fmt.Sprintf("%s %s: %s", o.Type, o.Name, action),
+		schemaObject: o,
+	}
+}
+
+func normalizeDefinition(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// execStatements executes the statements in the database on a single connection,
+// so that unqualified names in the definitions of schema objects are resolved in the database.
+// The database is selected once it exists, because it may be created by the statements. Empty database is never selected.
+func execStatements(ctx context.Context, db *sql.DB, database string, statements []string, label string) error {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	selected := false
+	for _, s := range statements {
+		if !selected && database != "" {
+			_, err := conn.ExecContext(ctx, fmt.Sprintf("USE `%s`", database))
+			selected = err == nil
+		}
+		tflog.Info(ctx, fmt.Sprintf("%s executing statements: %s", label, s))
+		_, err := conn.ExecContext(ctx, s)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
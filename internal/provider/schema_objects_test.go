@@ -0,0 +1,113 @@
+package provider
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestSplitStatements(t *testing.T) {
+	statements := splitStatements(`
+CREATE TABLE users
+(
+    id   int PRIMARY KEY,
+    name varchar(100) COMMENT 'first; last' -- name; of the user
+);
+/* comment; */
+DELIMITER //
+CREATE TRIGGER users_bi BEFORE INSERT ON users FOR EACH ROW
+BEGIN
+    SET NEW.name = TRIM(NEW.name);
+END//
+DELIMITER ;
+CREATE VIEW names AS SELECT name FROM users;
+`)
+	require.Len(t, statements, 3)
+	require.Contains(t, statements[0], "COMMENT 'first; last' -- name; of the user")
+	require.Equal(t, "CREATE TRIGGER users_bi BEFORE INSERT ON users FOR EACH ROW\nBEGIN\n    SET NEW.name = TRIM(NEW.name);\nEND", statements[1])
+	require.Equal(t, "CREATE VIEW names AS SELECT name FROM users", statements[2])
+}
+
+func TestSplitSchema(t *testing.T) {
+	schemaStr := `
+CREATE DATABASE example;
+USE example;
+CREATE TABLE users (id int PRIMARY KEY, name varchar(100));
+-- active users
+CREATE OR REPLACE ALGORITHM=MERGE DEFINER=CURRENT_USER SQL SECURITY INVOKER VIEW example.active_users AS SELECT * FROM users;
+CREATE TRIGGER ` + "`users_bi`" + ` BEFORE INSERT ON ` + "`example`.`users`" + ` FOR EACH ROW SET NEW.name = TRIM(NEW.name);
+CREATE FUNCTION hello (s char(20)) RETURNS char(50) DETERMINISTIC RETURN CONCAT('Hello, ', s, '!');
+CREATE EVENT IF NOT EXISTS cleanup ON SCHEDULE EVERY 1 DAY DO DELETE FROM users WHERE name IS NULL;
+`
+	tableSchemaStr, objects := splitSchema(schemaStr)
+	require.Equal(t, "CREATE DATABASE example;\nUSE example;\nCREATE TABLE users (id int PRIMARY KEY, name varchar(100));\n", tableSchemaStr)
+	require.Len(t, objects, 4)
+	require.Equal(t, &schemaObject{Type: "view", Name: "active_users", Definition: "CREATE OR REPLACE ALGORITHM=MERGE DEFINER=CURRENT_USER SQL SECURITY INVOKER VIEW example.active_users AS SELECT * FROM users"}, objects[0])
+	require.Equal(t, "trigger", objects[1].Type)
+	require.Equal(t, "users_bi", objects[1].Name)
+	require.Equal(t, "users", objects[1].Table)
+	require.Equal(t, "function", objects[2].Type)
+	require.Equal(t, "hello", objects[2].Name)
+	require.Equal(t, "event", objects[3].Type)
+	require.Equal(t, "cleanup", objects[3].Name)
+
+	// Schema without objects is returned as is
+	tableSchemaStr, objects = splitSchema("CREATE DATABASE example;\n")
+	require.Equal(t, "CREATE DATABASE example;\n", tableSchemaStr)
+	require.Empty(t, objects)
+}
+
+func TestSchemaObjectChanges(t *testing.T) {
+	_, remote := splitSchema(`
+CREATE VIEW v1 AS SELECT 1;
+CREATE VIEW v2 AS SELECT 2;
+CREATE TRIGGER t1 BEFORE INSERT ON users FOR EACH ROW SET NEW.id = 1;
+CREATE PROCEDURE p1 () SELECT 1;
+`)
+	_, previous := splitSchema(`
+CREATE VIEW v1 AS SELECT 1;
+CREATE VIEW v2 AS SELECT 2;
+CREATE TRIGGER t1 BEFORE INSERT ON users FOR EACH ROW SET NEW.id = 1;
+CREATE PROCEDURE p1 () SELECT 1;
+`)
+	_, local := splitSchema(`
+CREATE VIEW v1 AS SELECT 1;
+CREATE VIEW v2 AS SELECT 22;
+CREATE TRIGGER t1 BEFORE INSERT ON users FOR EACH ROW SET NEW.id = 2;
+CREATE EVENT e1 ON SCHEDULE EVERY 1 DAY DO SELECT 1;
+`)
+
+	pre, post := schemaObjectChanges("example", remote, previous, local)
+	require.Len(t, pre, 2)
+	require.Equal(t, "DROP PROCEDURE IF EXISTS `example`.`p1`;", pre[0].Sql)
+	require.Equal(t, "drop", pre[0].Kind)
+	require.Equal(t, "DROP TRIGGER IF EXISTS `example`.`t1`;", pre[1].Sql)
+	require.Equal(t, "alter", pre[1].Kind)
+	require.Len(t, post, 3)
+	require.Equal(t, "CREATE OR REPLACE VIEW v2 AS SELECT 22;", post[0].Sql)
+	require.Equal(t, "CREATE TRIGGER t1 BEFORE INSERT ON users FOR EACH ROW SET NEW.id = 2;", post[1].Sql)
+	require.Equal(t, "CREATE EVENT e1 ON SCHEDULE EVERY 1 DAY DO SELECT 1;", post[2].Sql)
+	require.Equal(t, "create", post[2].Kind)
+
+	changes := append(pre, post...)
+	require.Equal(t, "+1 event, ~1 view, ~1 trigger, -1 procedure", changeSummary(changes))
+
+	// Only creation and deletion are detected as drift
+	pre, post = schemaObjectChanges("example", remote, local, local)
+	require.Len(t, pre, 1)
+	require.Len(t, post, 1)
+	require.Equal(t, "CREATE EVENT e1 ON SCHEDULE EVERY 1 DAY DO SELECT 1;", post[0].Sql)
+}
+
+func TestFormatSchemaObjects(t *testing.T) {
+	_, objects := splitSchema(`
+CREATE VIEW v1 AS SELECT 1;
+DELIMITER //
+CREATE PROCEDURE p1 () BEGIN SELECT 1; SELECT 2; END//
+DELIMITER ;
+`)
+	formatted := formatSchemaObjects(objects)
+	require.Equal(t, "CREATE VIEW v1 AS SELECT 1;\nDELIMITER //\nCREATE PROCEDURE p1 () BEGIN SELECT 1; SELECT 2; END//\nDELIMITER ;\n", formatted)
+
+	_, parsed := splitSchema(formatted)
+	require.Equal(t, objects, parsed)
+}
@@ -0,0 +1,269 @@
+package provider
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/kota65535/alternator/lib"
+	"github.com/kota65535/alternator/parser"
+	"strings"
+)
+
+// tablesSchema returns the computed attribute describing table structures.
+func tablesSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Computed:    true,
+		Description: "Structured metadata of the tables in the database.",
+		Elem: &schema.Resource{
+			Schema: tableMetadataSchema(),
+		},
+	}
+}
+
+func tableMetadataSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"name": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Table name.",
+		},
+		"engine": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Storage engine of the table.",
+		},
+		"collation": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Default collation of the table.",
+		},
+		"columns": {
+			Type:        schema.TypeList,
+			Computed:    true,
+			Description: "Columns of the table.",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"name": {
+						Type:        schema.TypeString,
+						Computed:    true,
+						Description: "Column name.",
+					},
+					"type": {
+						Type:        schema.TypeString,
+						Computed:    true,
+						Description: "Data type of the column.",
+					},
+					"nullable": {
+						Type:        schema.TypeBool,
+						Computed:    true,
+						Description: "Whether the column accepts NULL.",
+					},
+					"default": {
+						Type:        schema.TypeString,
+						Computed:    true,
+						Description: "Default value expression of the column.",
+					},
+					"extra": {
+						Type:        schema.TypeString,
+						Computed:    true,
+						Description: "Additional information such as `auto_increment` or `on update CURRENT_TIMESTAMP`.",
+					},
+				},
+			},
+		},
+		"indexes": {
+			Type:        schema.TypeList,
+			Computed:    true,
+			Description: "Indexes of the table, including the primary key.",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"name": {
+						Type:        schema.TypeString,
+						Computed:    true,
+						Description: "Index name. `PRIMARY` for the primary key.",
+					},
+					"type": {
+						Type:        schema.TypeString,
+						Computed:    true,
+						Description: "Index type. One of `primary`, `unique`, `index` or `fulltext`.",
+					},
+					"columns": {
+						Type:        schema.TypeList,
+						Computed:    true,
+						Description: "Indexed columns or expressions.",
+						Elem: &schema.Schema{
+							Type: schema.TypeString,
+						},
+					},
+				},
+			},
+		},
+		"foreign_keys": {
+			Type:        schema.TypeList,
+			Computed:    true,
+			Description: "Foreign keys of the table.",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"name": {
+						Type:        schema.TypeString,
+						Computed:    true,
+						Description: "Foreign key name.",
+					},
+					"columns": {
+						Type:        schema.TypeList,
+						Computed:    true,
+						Description: "Referencing columns.",
+						Elem: &schema.Schema{
+							Type: schema.TypeString,
+						},
+					},
+					"referenced_table": {
+						Type:        schema.TypeString,
+						Computed:    true,
+						Description: "Referenced table name.",
+					},
+					"referenced_columns": {
+						Type:        schema.TypeList,
+						Computed:    true,
+						Description: "Referenced columns.",
+						Elem: &schema.Schema{
+							Type: schema.TypeString,
+						},
+					},
+					"on_delete": {
+						Type:        schema.TypeString,
+						Computed:    true,
+						Description: "Referential action on delete.",
+					},
+					"on_update": {
+						Type:        schema.TypeString,
+						Computed:    true,
+						Description: "Referential action on update.",
+					},
+				},
+			},
+		},
+	}
+}
+
+func flattenSchemaTables(schemas []*lib.Schema) []interface{} {
+	ret := []interface{}{}
+	for _, s := range schemas {
+		for _, t := range s.Tables {
+			ret = append(ret, flattenTable(t))
+		}
+	}
+	return ret
+}
+
+func flattenTable(t *parser.CreateTableStatement) map[string]interface{} {
+	engine := t.TableOptions.Engine
+	// Engine is unset by alternator if it is InnoDB, which is default
+	if engine == "" {
+		engine = "InnoDB"
+	}
+	collation := t.TableOptions.DefaultCollate
+	if t.TableOptions.DatabaseOptions != nil {
+		collation = t.TableOptions.ActualDefaultCollate()
+	}
+
+	columns := []interface{}{}
+	for _, c := range t.GetColumns() {
+		columns = append(columns, map[string]interface{}{
+			"name":     c.ColumnName,
+			"type":     fmt.Sprint(c.DataType),
+			"nullable": c.ColumnOptions.Nullability != "NOT NULL",
+			"default":  c.ColumnOptions.Default,
+			"extra":    columnExtra(c.ColumnOptions),
+		})
+	}
+
+	indexes := []interface{}{}
+	for _, i := range t.GetPrimaryKeys() {
+		indexes = append(indexes, map[string]interface{}{
+			"name":    "PRIMARY",
+			"type":    "primary",
+			"columns": keyPartNames(i.KeyPartList),
+		})
+	}
+	for _, i := range t.GetUniqueKeys() {
+		indexes = append(indexes, map[string]interface{}{
+			"name":    i.IndexName,
+			"type":    "unique",
+			"columns": keyPartNames(i.KeyPartList),
+		})
+	}
+	for _, i := range t.GetIndexes() {
+		indexes = append(indexes, map[string]interface{}{
+			"name":    i.IndexName,
+			"type":    "index",
+			"columns": keyPartNames(i.KeyPartList),
+		})
+	}
+	for _, i := range t.GetFullTextIndexes() {
+		indexes = append(indexes, map[string]interface{}{
+			"name":    i.IndexName,
+			"type":    "fulltext",
+			"columns": keyPartNames(i.KeyPartList),
+		})
+	}
+
+	foreignKeys := []interface{}{}
+	for _, f := range t.GetForeignKeys() {
+		name := f.ConstraintName
+		if name == "" {
+			name = f.IndexName
+		}
+		foreignKeys = append(foreignKeys, map[string]interface{}{
+			"name":               name,
+			"columns":            keyPartNames(f.KeyPartList),
+			"referenced_table":   f.ReferenceDefinition.TableName,
+			"referenced_columns": keyPartNames(f.ReferenceDefinition.KeyPartList),
+			"on_delete":          f.ReferenceDefinition.ReferenceOptions.OnDelete,
+			"on_update":          f.ReferenceDefinition.ReferenceOptions.OnUpdate,
+		})
+	}
+
+	return map[string]interface{}{
+		"name":         t.TableName,
+		"engine":       engine,
+		"collation":    collation,
+		"columns":      columns,
+		"indexes":      indexes,
+		"foreign_keys": foreignKeys,
+	}
+}
+
+// columnExtra builds a string similar to the EXTRA column of information_schema.COLUMNS.
+func columnExtra(o parser.ColumnOptions) string {
+	extras := []string{}
+	if o.AutoIncrement {
+		extras = append(extras, "auto_increment")
+	}
+	if o.OnUpdate != "" {
+		extras = append(extras, fmt.Sprintf("on update %s", o.OnUpdate))
+	}
+	if o.GeneratedAs != "" {
+		generatedType := o.GeneratedColumnType
+		if generatedType == "" {
+			generatedType = "VIRTUAL"
+		}
+		extras = append(extras, fmt.Sprintf("%s GENERATED", strings.ToUpper(generatedType)))
+	}
+	if o.Visibility != "" {
+		extras = append(extras, strings.ToUpper(o.Visibility))
+	}
+	return strings.Join(extras, " ")
+}
+
+func keyPartNames(keyParts []parser.KeyPart) []string {
+	ret := []string{}
+	for _, k := range keyParts {
+		if k.Column != "" {
+			ret = append(ret, k.Column)
+		} else {
+			ret = append(ret, k.Expression)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,2 @@
+INSERT INTO greeting (body) VALUES ('hello');
+INSERT INTO missing_table (body) VALUES ('oops');